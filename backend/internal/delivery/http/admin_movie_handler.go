@@ -0,0 +1,327 @@
+package deliveryhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	stdhttp "net/http"
+	"strconv"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// Admin movie list page sizes
+const (
+	defaultAdminMovieLimit = 50
+	maxAdminMovieLimit     = 200
+)
+
+// AdminMovieHandler lets admins edit the stored movie catalog
+type AdminMovieHandler struct {
+	movies *usecase.MovieUsecase
+}
+
+func NewAdminMovieHandler(movies *usecase.MovieUsecase) *AdminMovieHandler {
+	return &AdminMovieHandler{movies: movies}
+}
+
+// AdminMovieResponse is a stored movie with its bookkeeping fields
+type AdminMovieResponse struct {
+	MovieResponse
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+func newAdminMovieResponse(m domain.Movie) AdminMovieResponse {
+	resp := AdminMovieResponse{MovieResponse: newMovieResponse(m), CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt}
+	if m.DeletedAt.Valid {
+		resp.DeletedAt = &m.DeletedAt.Time
+	}
+	return resp
+}
+
+// CreateMovieRequest adds a movie. A movie whose TMDB or IMDb ID is
+// already stored updates that one instead.
+type CreateMovieRequest struct {
+	MediaType     string   `json:"media_type" binding:"omitempty,oneof=movie tv"`
+	TMDBID        *uint    `json:"tmdb_id"`
+	IMDbID        *string  `json:"imdb_id"`
+	Title         string   `json:"title" binding:"required"`
+	OriginalTitle string   `json:"original_title"`
+	Description   string   `json:"description"`
+	Year          int      `json:"year"`
+	GenreIDs      []uint   `json:"genre_ids"`
+	CastNames     []string `json:"cast_names"`
+}
+
+// UpdateMovieRequest edits a movie; omitted fields are left as they are.
+// An empty imdb_id or a zero tmdb_id clears it.
+type UpdateMovieRequest struct {
+	MediaType     *string   `json:"media_type" binding:"omitempty,oneof=movie tv"`
+	TMDBID        *uint     `json:"tmdb_id"`
+	IMDbID        *string   `json:"imdb_id"`
+	Title         *string   `json:"title"`
+	OriginalTitle *string   `json:"original_title"`
+	Description   *string   `json:"description"`
+	Year          *int      `json:"year"`
+	GenreIDs      *[]uint   `json:"genre_ids"`
+	CastNames     *[]string `json:"cast_names"`
+}
+
+// MovieAuditResponse is one entry in a movie's edit history
+type MovieAuditResponse struct {
+	domain.MovieAudit
+	Changes json.RawMessage `json:"changes"`
+}
+
+// ListMovies pages through stored movies, newest first
+// (GET /api/admin/movies?title=&include_deleted=true&page=&limit=)
+func (h *AdminMovieHandler) ListMovies(c *gin.Context) {
+	q := domain.MovieListQuery{Title: c.Query("title"), Limit: defaultAdminMovieLimit}
+	if raw := c.Query("include_deleted"); raw != "" {
+		includeDeleted, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "include_deleted must be true or false"})
+			return
+		}
+		q.IncludeDeleted = includeDeleted
+	}
+	if raw := c.Query("limit"); raw != "" {
+		l, err := strconv.Atoi(raw)
+		if err != nil || l < 1 || l > maxAdminMovieLimit {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxAdminMovieLimit)})
+			return
+		}
+		q.Limit = l
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "page must be a positive number"})
+		return
+	}
+	q.Offset = (page - 1) * q.Limit
+
+	movies, total, err := h.movies.ListMovies(q)
+	if err != nil {
+		log.Printf("Error listing movies: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to list movies"})
+		return
+	}
+	results := make([]AdminMovieResponse, len(movies))
+	for i, m := range movies {
+		results[i] = newAdminMovieResponse(m)
+	}
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"movies":        results,
+		"page":          page,
+		"total_results": total,
+		"total_pages":   (total + int64(q.Limit) - 1) / int64(q.Limit),
+	})
+}
+
+// GetMovie returns one movie, deleted or not (GET /api/admin/movies/:id)
+func (h *AdminMovieHandler) GetMovie(c *gin.Context) {
+	id, ok := adminMovieID(c)
+	if !ok {
+		return
+	}
+	movie, err := h.movies.GetMovie(id)
+	if err != nil {
+		log.Printf("Error loading movie %d: %v", id, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to load movie"})
+		return
+	}
+	if movie == nil {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Movie not found"})
+		return
+	}
+	c.JSON(stdhttp.StatusOK, newAdminMovieResponse(*movie))
+}
+
+// CreateMovie adds a movie, or updates and restores the one with the same
+// external IDs (POST /api/admin/movies)
+func (h *AdminMovieHandler) CreateMovie(c *gin.Context) {
+	var req CreateMovieRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	movie := &domain.Movie{
+		MediaType:     req.MediaType,
+		TMDBID:        req.TMDBID,
+		IMDbID:        req.IMDbID,
+		Title:         req.Title,
+		OriginalTitle: req.OriginalTitle,
+		Description:   req.Description,
+		Year:          req.Year,
+		CastNames:     req.CastNames,
+	}
+	if req.GenreIDs != nil {
+		movie.Genres = make([]domain.Genre, len(req.GenreIDs))
+		for i, id := range req.GenreIDs {
+			movie.Genres[i] = domain.Genre{ID: id}
+		}
+	}
+
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	movie, created, err := h.movies.AddMovie(userID, movie)
+	if err != nil {
+		if !movieEditError(c, err) {
+			log.Printf("Error creating movie: %v", err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to create movie"})
+		}
+		return
+	}
+	status := stdhttp.StatusOK
+	if created {
+		status = stdhttp.StatusCreated
+	}
+	c.JSON(status, newAdminMovieResponse(*movie))
+}
+
+// UpdateMovie edits a movie (PATCH /api/admin/movies/:id)
+func (h *AdminMovieHandler) UpdateMovie(c *gin.Context) {
+	id, ok := adminMovieID(c)
+	if !ok {
+		return
+	}
+	var req UpdateMovieRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	patch := domain.MoviePatch{
+		MediaType:     req.MediaType,
+		TMDBID:        req.TMDBID,
+		IMDbID:        req.IMDbID,
+		Title:         req.Title,
+		OriginalTitle: req.OriginalTitle,
+		Description:   req.Description,
+		Year:          req.Year,
+		GenreIDs:      req.GenreIDs,
+		CastNames:     req.CastNames,
+	}
+
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	movie, err := h.movies.UpdateMovie(userID, id, patch)
+	if err != nil {
+		if !movieEditError(c, err) {
+			log.Printf("Error updating movie %d: %v", id, err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to update movie"})
+		}
+		return
+	}
+	c.JSON(stdhttp.StatusOK, newAdminMovieResponse(*movie))
+}
+
+// DeleteMovie hides a movie from listings and search; it can be restored
+// (DELETE /api/admin/movies/:id)
+func (h *AdminMovieHandler) DeleteMovie(c *gin.Context) {
+	id, ok := adminMovieID(c)
+	if !ok {
+		return
+	}
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	if err := h.movies.DeleteMovie(userID, id); err != nil {
+		if !movieEditError(c, err) {
+			log.Printf("Error deleting movie %d: %v", id, err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to delete movie"})
+		}
+		return
+	}
+	c.Status(stdhttp.StatusNoContent)
+}
+
+// RestoreMovie undoes a delete (POST /api/admin/movies/:id/restore)
+func (h *AdminMovieHandler) RestoreMovie(c *gin.Context) {
+	id, ok := adminMovieID(c)
+	if !ok {
+		return
+	}
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	movie, err := h.movies.RestoreMovie(userID, id)
+	if err != nil {
+		if !movieEditError(c, err) {
+			log.Printf("Error restoring movie %d: %v", id, err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to restore movie"})
+		}
+		return
+	}
+	c.JSON(stdhttp.StatusOK, newAdminMovieResponse(*movie))
+}
+
+// GetMovieHistory lists who changed a movie and how, newest first
+// (GET /api/admin/movies/:id/history)
+func (h *AdminMovieHandler) GetMovieHistory(c *gin.Context) {
+	id, ok := adminMovieID(c)
+	if !ok {
+		return
+	}
+	audits, err := h.movies.MovieHistory(id)
+	if err != nil {
+		log.Printf("Error loading history of movie %d: %v", id, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to load history"})
+		return
+	}
+	history := make([]MovieAuditResponse, len(audits))
+	for i, a := range audits {
+		history[i] = MovieAuditResponse{MovieAudit: a, Changes: json.RawMessage(a.Changes)}
+	}
+	c.JSON(stdhttp.StatusOK, gin.H{"history": history})
+}
+
+// adminMovieID reads the :id path parameter
+func adminMovieID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid movie ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// movieEditError answers the errors a movie edit can expect, reporting
+// false for anything else
+func movieEditError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidMovie):
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Movie not found"})
+	case errors.Is(err, repository.ErrExternalIDConflict):
+		c.JSON(stdhttp.StatusConflict, gin.H{"error": "TMDB or IMDb ID already belongs to another movie"})
+	default:
+		return false
+	}
+	return true
+}
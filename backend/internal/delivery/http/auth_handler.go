@@ -1,8 +1,10 @@
 package deliveryhttp
 
 import (
+	"errors"
 	"log"
 	stdhttp "net/http"
+	"strings"
 
 	"github.com/HMZ-H/moviemate/internal/domain"
 	"github.com/HMZ-H/moviemate/internal/infra"
@@ -41,10 +43,19 @@ type AuthResponse struct {
 }
 
 type UserResponse struct {
-	ID        uint   `json:"id"`
-	Username  string `json:"username"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"created_at"`
+	ID             uint   `json:"id"`
+	Username       string `json:"username"`
+	Email          string `json:"email"`
+	Region         string `json:"region"`
+	EmailReminders bool   `json:"email_reminders"`
+	Role           string `json:"role"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// UpdateProfileRequest changes profile settings; omitted fields are left as they are
+type UpdateProfileRequest struct {
+	Region         *string `json:"region" binding:"omitempty,len=2,alpha"`
+	EmailReminders *bool   `json:"email_reminders"`
 }
 
 // Register creates a new user account
@@ -178,10 +189,13 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	}
 
 	userResponse := &UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:             user.ID,
+		Username:       user.Username,
+		Email:          user.Email,
+		Region:         user.Region,
+		EmailReminders: user.EmailReminders,
+		Role:           user.Role,
+		CreatedAt:      user.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
 
 	c.JSON(stdhttp.StatusOK, gin.H{
@@ -190,6 +204,37 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	})
 }
 
+// UpdateProfile changes the current user's region and reminder settings
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	patch := domain.ProfilePatch{EmailReminders: req.EmailReminders}
+	if req.Region != nil {
+		region := strings.ToUpper(*req.Region)
+		patch.Region = &region
+	}
+	if err := h.userRepo.UpdateProfile(userID.(uint), patch); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(stdhttp.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		log.Printf("Error updating profile: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	h.GetProfile(c)
+}
+
 // AuthMiddleware validates JWT tokens
 func (h *AuthHandler) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -217,3 +262,29 @@ func (h *AuthHandler) AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireAdmin lets only admins through. It runs after AuthMiddleware and
+// reads the role from the database, so a demotion takes effect immediately.
+func (h *AuthHandler) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("user_id")
+		if !ok {
+			c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+		role, err := h.userRepo.GetUserRole(userID.(uint))
+		if err != nil {
+			log.Printf("Error loading role of user %v: %v", userID, err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to check permissions"})
+			c.Abort()
+			return
+		}
+		if role != domain.UserRoleAdmin {
+			c.JSON(stdhttp.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,159 @@
+package deliveryhttp
+
+import (
+	"log"
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// maxUserServices bounds how many streaming services a user can save
+const maxUserServices = 50
+
+type UserServicesRequest struct {
+	ProviderIDs []int `json:"provider_ids"`
+}
+
+type AvailabilityHandler struct {
+	repo         repository.AvailabilityRepo
+	metadata     *usecase.MetadataCache
+	availability *usecase.AvailabilityCache
+}
+
+func NewAvailabilityHandler(repo repository.AvailabilityRepo, metadata *usecase.MetadataCache, availability *usecase.AvailabilityCache) *AvailabilityHandler {
+	return &AvailabilityHandler{repo: repo, metadata: metadata, availability: availability}
+}
+
+// GetUserServices returns the TMDB provider IDs the user subscribes to
+// (GET /api/profile/services)
+func (h *AvailabilityHandler) GetUserServices(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	services, err := h.repo.GetUserServices(userID)
+	if err != nil {
+		log.Printf("Error fetching user services: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{"provider_ids": services})
+}
+
+// SetUserServices replaces the user's streaming services (PUT /api/profile/services)
+func (h *AvailabilityHandler) SetUserServices(c *gin.Context) {
+	var req UserServicesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.ProviderIDs) > maxUserServices {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Too many services"})
+		return
+	}
+	seen := map[int]bool{}
+	ids := make([]int, 0, len(req.ProviderIDs))
+	for _, id := range req.ProviderIDs {
+		if id <= 0 {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "provider_ids must be TMDB watch provider IDs"})
+			return
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	if err := h.repo.SetUserServices(userID, ids); err != nil {
+		log.Printf("Error saving user services: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to save services",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+		Message: "Services saved",
+		Success: true,
+	})
+}
+
+// GetWatchProviders returns where one title can be watched
+// (GET /api/watch-providers/:movie_id?region=). The region defaults to the user's.
+func (h *AvailabilityHandler) GetWatchProviders(c *gin.Context) {
+	movieID, err := strconv.ParseUint(c.Param("movie_id"), 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid movie ID"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	region := strings.ToUpper(c.Query("region"))
+	if region == "" {
+		if region, err = h.repo.GetUserRegion(userID); err != nil {
+			log.Printf("Error fetching user region: %v", err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watch providers"})
+			return
+		}
+		if region == "" {
+			region = defaultRegion
+		}
+	} else if len(region) != 2 {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "region must be a two-letter country code"})
+		return
+	}
+
+	id := uint(movieID)
+	meta, err := h.metadata.Lookup([]uint{id})
+	if err != nil {
+		log.Printf("Error fetching metadata: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watch providers"})
+		return
+	}
+	if _, ok := meta[id]; !ok {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Title not found"})
+		return
+	}
+	offers, err := h.availability.Lookup(meta, region)
+	if err != nil {
+		log.Printf("Error loading watch providers: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watch providers"})
+		return
+	}
+	providers := offers[id]
+	if providers == nil {
+		providers = []domain.WatchOffer{}
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"movie_id":  id,
+		"region":    region,
+		"providers": providers,
+	})
+}
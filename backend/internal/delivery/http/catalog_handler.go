@@ -0,0 +1,287 @@
+package deliveryhttp
+
+import (
+	"fmt"
+	"log"
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// catalogCacheControl lets browsers and CDNs reuse catalog responses; TMDB
+// itself only refreshes trending and popular lists a few times a day
+const catalogCacheControl = "public, max-age=600"
+
+// TMDB serves at most 500 pages of any listing
+const maxCatalogPage = 500
+
+// maxSearchQuery bounds the length of a catalog search
+const maxSearchQuery = 200
+
+// catalogLists are the curated lists exposed per media type
+var catalogLists = map[string]map[string]bool{
+	"movie": {"popular": true, "top_rated": true, "upcoming": true, "now_playing": true},
+	"tv":    {"popular": true, "top_rated": true, "on_the_air": true, "airing_today": true},
+}
+
+// discoverSorts are the sort_by fields /discover accepts, each with .asc or .desc
+var discoverSorts = map[string]bool{
+	"popularity": true, "vote_average": true, "vote_count": true,
+	"primary_release_date": true, "first_air_date": true, "revenue": true,
+}
+
+// CatalogHandler proxies a curated slice of TMDB so the API credential stays
+// on the server
+type CatalogHandler struct {
+	catalog domain.CatalogProvider
+	cache   *usecase.CachedCatalog
+}
+
+// NewCatalogHandler creates the handler; catalog and cache are nil when
+// neither TMDB nor a fixture is configured
+func NewCatalogHandler(catalog domain.CatalogProvider, cache *usecase.CachedCatalog) *CatalogHandler {
+	return &CatalogHandler{catalog: catalog, cache: cache}
+}
+
+// GetTrending returns trending titles
+// (GET /api/catalog/trending?media_type=all|movie|tv&window=day|week&page=)
+func (h *CatalogHandler) GetTrending(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	mediaType := c.DefaultQuery("media_type", "all")
+	if mediaType != "all" && !validMediaType(mediaType) {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "media_type must be all, movie or tv"})
+		return
+	}
+	window := c.DefaultQuery("window", "week")
+	if window != "day" && window != "week" {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "window must be day or week"})
+		return
+	}
+	page, ok := catalogPage(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.catalog.Trending(mediaType, window, page)
+	h.respond(c, result, err, "trending titles")
+}
+
+// GetDiscover filters the catalog (GET /api/catalog/discover?media_type=movie|tv
+// &genres=28,12&year=&released_from=YYYY-MM-DD&released_to=&sort_by=popularity.desc&page=)
+func (h *CatalogHandler) GetDiscover(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	q := domain.DiscoverQuery{MediaType: c.DefaultQuery("media_type", "movie")}
+	if !validMediaType(q.MediaType) {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "media_type must be movie or tv"})
+		return
+	}
+	if genres := c.Query("genres"); genres != "" {
+		for _, g := range strings.Split(genres, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(g))
+			if err != nil || id <= 0 {
+				c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "genres must be comma-separated TMDB genre IDs"})
+				return
+			}
+			q.Genres = append(q.Genres, id)
+		}
+	}
+	if year := c.Query("year"); year != "" {
+		y, err := strconv.Atoi(year)
+		if err != nil || y < 1870 || y > 2100 {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+		q.Year = y
+	}
+	for _, d := range []struct {
+		param string
+		dst   *string
+	}{{"released_from", &q.ReleasedFrom}, {"released_to", &q.ReleasedTo}} {
+		v := c.Query(d.param)
+		if v == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": d.param + " must be a YYYY-MM-DD date"})
+			return
+		}
+		*d.dst = v
+	}
+	q.SortBy = c.DefaultQuery("sort_by", "popularity.desc")
+	field, dir, _ := strings.Cut(q.SortBy, ".")
+	if !discoverSorts[field] || (dir != "asc" && dir != "desc") {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid sort_by"})
+		return
+	}
+	var ok bool
+	if q.Page, ok = catalogPage(c); !ok {
+		return
+	}
+
+	result, err := h.catalog.Discover(q)
+	h.respond(c, result, err, "discover results")
+}
+
+// Search finds movies, shows and people (GET /api/catalog/search?q=&page=)
+func (h *CatalogHandler) Search(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" || len(query) > maxSearchQuery {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("q must be 1 to %d characters", maxSearchQuery)})
+		return
+	}
+	page, ok := catalogPage(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.catalog.Search(query, page)
+	h.respond(c, result, err, "search results")
+}
+
+// GetTitle returns a movie or show with credits and videos
+// (GET /api/catalog/:media_type/:id). :id may instead name a curated list,
+// e.g. /api/catalog/movie/top_rated?page=2 or /api/catalog/tv/on_the_air.
+func (h *CatalogHandler) GetTitle(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	mediaType := c.Param("media_type")
+	if !validMediaType(mediaType) {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Unknown media type"})
+		return
+	}
+	if list := c.Param("id"); catalogLists[mediaType][list] {
+		page, ok := catalogPage(c)
+		if !ok {
+			return
+		}
+		result, err := h.catalog.List(mediaType, list, page)
+		h.respond(c, result, err, "list")
+		return
+	}
+	id, ok := catalogID(c)
+	if !ok {
+		return
+	}
+
+	details, err := h.catalog.Details(mediaType, id)
+	if err == nil && details == nil {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Title not found"})
+		return
+	}
+	h.respond(c, details, err, "title details")
+}
+
+// GetVideos returns trailers and clips (GET /api/catalog/:media_type/:id/videos)
+func (h *CatalogHandler) GetVideos(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	mediaType := c.Param("media_type")
+	if !validMediaType(mediaType) {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Unknown media type"})
+		return
+	}
+	id, ok := catalogID(c)
+	if !ok {
+		return
+	}
+
+	videos, err := h.catalog.Videos(mediaType, id)
+	if err == nil && videos == nil {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Title not found"})
+		return
+	}
+	h.respond(c, videos, err, "videos")
+}
+
+// GetImages returns backdrops, posters and logos
+// (GET /api/catalog/:media_type/:id/images)
+func (h *CatalogHandler) GetImages(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	mediaType := c.Param("media_type")
+	if !validMediaType(mediaType) {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Unknown media type"})
+		return
+	}
+	id, ok := catalogID(c)
+	if !ok {
+		return
+	}
+
+	images, err := h.catalog.Images(mediaType, id)
+	if err == nil && images == nil {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Title not found"})
+		return
+	}
+	h.respond(c, images, err, "images")
+}
+
+// GetCacheStats returns the catalog cache's hit and miss counters per
+// endpoint since startup; admins only (GET /api/admin/catalog/cache-stats)
+func (h *CatalogHandler) GetCacheStats(c *gin.Context) {
+	if h.cache == nil {
+		c.JSON(stdhttp.StatusServiceUnavailable, gin.H{"error": "Catalog is not available"})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(stdhttp.StatusOK, h.cache.Stats())
+}
+
+func (h *CatalogHandler) available(c *gin.Context) bool {
+	if h.catalog == nil {
+		c.JSON(stdhttp.StatusServiceUnavailable, gin.H{"error": "Catalog is not available"})
+		return false
+	}
+	return true
+}
+
+// respond writes a successful result with cache headers, or reports that
+// TMDB couldn't be reached
+func (h *CatalogHandler) respond(c *gin.Context, result any, err error, what string) {
+	if err != nil {
+		log.Printf("Error fetching %s from TMDB: %v", what, err)
+		c.JSON(stdhttp.StatusBadGateway, gin.H{"error": "Failed to fetch " + what})
+		return
+	}
+	c.Header("Cache-Control", catalogCacheControl)
+	c.JSON(stdhttp.StatusOK, result)
+}
+
+func validMediaType(mediaType string) bool {
+	return mediaType == "movie" || mediaType == "tv"
+}
+
+// catalogPage reads the page query parameter, defaulting to 1
+func catalogPage(c *gin.Context) (int, bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 || page > maxCatalogPage {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("page must be between 1 and %d", maxCatalogPage)})
+		return 0, false
+	}
+	return page, true
+}
+
+// catalogID reads the :id path parameter
+func catalogID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid title ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
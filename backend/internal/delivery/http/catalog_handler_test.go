@@ -0,0 +1,135 @@
+package deliveryhttp_test
+
+import (
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	deliveryhttp "github.com/HMZ-H/moviemate/internal/delivery/http"
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/infra"
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// countingCatalog counts the title lookups that reach the provider it wraps
+type countingCatalog struct {
+	domain.CatalogProvider
+	details atomic.Int32
+}
+
+func (c *countingCatalog) Details(mediaType string, id uint) (*domain.CatalogDetails, error) {
+	c.details.Add(1)
+	return c.CatalogProvider.Details(mediaType, id)
+}
+
+// newFixtureCatalogRouter serves the catalog routes from testdata/catalog.json
+// through the cache, as the router does when TMDB_CATALOG_FIXTURE is set
+func newFixtureCatalogRouter(t *testing.T) (*gin.Engine, *countingCatalog, *usecase.CachedCatalog) {
+	t.Helper()
+	fixture, err := infra.NewFixtureCatalogService("../../infra/testdata/catalog.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream := &countingCatalog{CatalogProvider: fixture}
+	cache := usecase.NewCachedCatalog(upstream, nil, 100)
+	h := deliveryhttp.NewCatalogHandler(cache, cache)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/catalog/trending", h.GetTrending)
+	r.GET("/api/catalog/search", h.Search)
+	r.GET("/api/catalog/:media_type/:id", h.GetTitle)
+	r.GET("/api/catalog/:media_type/:id/videos", h.GetVideos)
+	return r, upstream, cache
+}
+
+func serve(r *gin.Engine, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(stdhttp.MethodGet, target, nil))
+	return w
+}
+
+func TestCatalogHandlerServesFixture(t *testing.T) {
+	r, _, _ := newFixtureCatalogRouter(t)
+
+	w := serve(r, "/api/catalog/trending?window=week")
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf("trending status = %d: %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=600" {
+		t.Errorf("trending Cache-Control = %q", got)
+	}
+	var page domain.CatalogPage
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Results) == 0 || page.Results[0].ID != 27205 || page.Results[0].Title != "Inception" {
+		t.Errorf("trending results = %+v, want Inception first", page.Results)
+	}
+
+	w = serve(r, "/api/catalog/movie/27205")
+	if w.Code != stdhttp.StatusOK {
+		t.Fatalf("details status = %d: %s", w.Code, w.Body)
+	}
+	var details domain.CatalogDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &details); err != nil {
+		t.Fatal(err)
+	}
+	if details.Title != "Inception" || details.IMDbID == "" {
+		t.Errorf("details = %+v, want Inception with an IMDb ID", details.CatalogItem)
+	}
+}
+
+func TestCatalogHandlerErrors(t *testing.T) {
+	r, _, _ := newFixtureCatalogRouter(t)
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/api/catalog/movie/1", stdhttp.StatusNotFound},
+		{"/api/catalog/movie/1/videos", stdhttp.StatusNotFound},
+		{"/api/catalog/movie/abc", stdhttp.StatusBadRequest},
+		{"/api/catalog/person/27205", stdhttp.StatusNotFound},
+		{"/api/catalog/trending?media_type=people", stdhttp.StatusBadRequest},
+		{"/api/catalog/trending?page=501", stdhttp.StatusBadRequest},
+		{"/api/catalog/search", stdhttp.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if w := serve(r, tt.target); w.Code != tt.want {
+			t.Errorf("GET %s = %d, want %d: %s", tt.target, w.Code, tt.want, w.Body)
+		}
+	}
+}
+
+func TestCatalogHandlerCachesLookups(t *testing.T) {
+	r, upstream, cache := newFixtureCatalogRouter(t)
+	for i := 0; i < 3; i++ {
+		if w := serve(r, "/api/catalog/movie/27205"); w.Code != stdhttp.StatusOK {
+			t.Fatalf("details status = %d: %s", w.Code, w.Body)
+		}
+	}
+	// "Not found" answers are cached as well
+	for i := 0; i < 2; i++ {
+		serve(r, "/api/catalog/movie/1")
+	}
+	if got := upstream.details.Load(); got != 2 {
+		t.Errorf("upstream detail lookups = %d, want 2", got)
+	}
+	stats := cache.Stats().Endpoints["details"]
+	if stats.Misses != 2 || stats.Hits != 3 {
+		t.Errorf("details stats = %+v, want 2 misses and 3 hits", stats)
+	}
+}
+
+func TestCatalogHandlerWithoutCatalog(t *testing.T) {
+	h := deliveryhttp.NewCatalogHandler(nil, nil)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/catalog/trending", h.GetTrending)
+	if w := serve(r, "/api/catalog/trending"); w.Code != stdhttp.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+}
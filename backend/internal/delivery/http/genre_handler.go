@@ -0,0 +1,35 @@
+package deliveryhttp
+
+import (
+	"log"
+	stdhttp "net/http"
+
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// GenreHandler serves the genre list used for filtering
+type GenreHandler struct {
+	genres *usecase.GenreService
+}
+
+func NewGenreHandler(genres *usecase.GenreService) *GenreHandler {
+	return &GenreHandler{genres: genres}
+}
+
+// ListGenres returns genres by name (GET /api/genres?media_type=movie|tv)
+func (h *GenreHandler) ListGenres(c *gin.Context) {
+	mediaType := c.Query("media_type")
+	if mediaType != "" && !validMediaType(mediaType) {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "media_type must be movie or tv"})
+		return
+	}
+	genres, err := h.genres.List(mediaType)
+	if err != nil {
+		log.Printf("Error listing genres: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to list genres"})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(stdhttp.StatusOK, gin.H{"genres": genres})
+}
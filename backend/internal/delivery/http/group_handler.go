@@ -0,0 +1,189 @@
+package deliveryhttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	stdhttp "net/http"
+	"strconv"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// Candidate count bounds for POST /api/groups/:id/pick
+const (
+	defaultPickLimit = 10
+	maxPickLimit     = 50
+)
+
+// PickRequest constrains a group pick; every field is optional
+type PickRequest struct {
+	Mode           string   `json:"mode"`        // intersection (default) or overlap
+	MinMembers     int      `json:"min_members"` // overlap only; defaults to 2
+	MaxRuntime     int      `json:"max_runtime"` // minutes
+	ExcludeGenres  []string `json:"exclude_genres"`
+	MediaType      string   `json:"media_type"`
+	IncludeWatched bool     `json:"include_watched"`
+	MemberIDs      []uint   `json:"member_ids"` // who's watching; defaults to the whole group
+	Limit          int      `json:"limit"`
+}
+
+// GroupHandler serves movie-night picks for the people on a shared list
+type GroupHandler struct {
+	lists  repository.ListRepo
+	users  repository.UserRepo
+	picker *usecase.GroupPicker
+}
+
+func NewGroupHandler(lists repository.ListRepo, users repository.UserRepo, picker *usecase.GroupPicker) *GroupHandler {
+	return &GroupHandler{lists: lists, users: users, picker: picker}
+}
+
+// Pick ranks titles from the group members' personal watchlists
+// (POST /api/groups/:id/pick). The group is shared list :id's owner and members.
+func (h *GroupHandler) Pick(c *gin.Context) {
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+	var req PickRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	opts, err := req.options()
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	if _, _, ok := checkListAccess(c, h.lists, uint(listID), userID, domain.RoleViewer); !ok {
+		return
+	}
+	members, err := h.groupMembers(uint(listID))
+	if err != nil {
+		log.Printf("Error loading group %d: %v", listID, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to load group"})
+		return
+	}
+	if len(req.MemberIDs) > 0 {
+		if members, err = selectMembers(members, req.MemberIDs); err != nil {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if opts.Mode == domain.PickOverlap && opts.MinMembers > len(members) {
+		opts.MinMembers = len(members)
+	}
+
+	candidates, err := h.picker.Pick(members, opts)
+	if err != nil {
+		log.Printf("Error picking for group %d: %v", listID, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to pick titles"})
+		return
+	}
+	names := make([]string, len(members))
+	for i, m := range members {
+		names[i] = m.Username
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"candidates": candidates,
+		"count":      len(candidates),
+		"mode":       opts.Mode,
+		"members":    names,
+	})
+}
+
+// groupMembers returns the list's owner followed by its members
+func (h *GroupHandler) groupMembers(listID uint) ([]domain.PickMember, error) {
+	list, err := h.lists.GetList(listID)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		return nil, repository.ErrNotFound
+	}
+	ownerName, err := h.users.GetUsername(list.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerName == "" {
+		return nil, repository.ErrNotFound
+	}
+	members := []domain.PickMember{{UserID: list.OwnerID, Username: ownerName}}
+	for _, m := range list.Members {
+		members = append(members, domain.PickMember{UserID: m.UserID, Username: m.Username})
+	}
+	return members, nil
+}
+
+// selectMembers narrows the group to the given user IDs
+func selectMembers(members []domain.PickMember, ids []uint) ([]domain.PickMember, error) {
+	byID := make(map[uint]domain.PickMember, len(members))
+	for _, m := range members {
+		byID[m.UserID] = m
+	}
+	seen := map[uint]bool{}
+	var selected []domain.PickMember
+	for _, id := range ids {
+		m, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("user %d is not in this group", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			selected = append(selected, m)
+		}
+	}
+	return selected, nil
+}
+
+// options validates the request and fills in defaults
+func (req PickRequest) options() (domain.PickOptions, error) {
+	opts := domain.PickOptions{
+		Mode:           req.Mode,
+		MinMembers:     req.MinMembers,
+		MaxRuntime:     req.MaxRuntime,
+		ExcludeGenres:  req.ExcludeGenres,
+		MediaType:      req.MediaType,
+		IncludeWatched: req.IncludeWatched,
+		Limit:          req.Limit,
+	}
+	switch opts.Mode {
+	case "":
+		opts.Mode = domain.PickIntersection
+	case domain.PickIntersection, domain.PickOverlap:
+	default:
+		return opts, errors.New("mode must be intersection or overlap")
+	}
+	if opts.MinMembers < 0 || opts.MaxRuntime < 0 {
+		return opts, errors.New("min_members and max_runtime can't be negative")
+	}
+	if opts.MinMembers == 0 {
+		opts.MinMembers = 2
+	}
+	if opts.MediaType != "" && opts.MediaType != "movie" && opts.MediaType != "tv" {
+		return opts, errors.New("media_type must be movie or tv")
+	}
+	if opts.Limit == 0 {
+		opts.Limit = defaultPickLimit
+	}
+	if opts.Limit < 0 || opts.Limit > maxPickLimit {
+		return opts, fmt.Errorf("limit must be between 1 and %d", maxPickLimit)
+	}
+	return opts, nil
+}
@@ -0,0 +1,60 @@
+package deliveryhttp
+
+import (
+	"errors"
+	"log"
+	stdhttp "net/http"
+	"slices"
+
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// ImageHandler serves posters, backdrops and profile photos at preset sizes
+type ImageHandler struct {
+	images *usecase.ImageProxy
+}
+
+func NewImageHandler(images *usecase.ImageProxy) *ImageHandler {
+	return &ImageHandler{images: images}
+}
+
+// GetImage returns a TMDB image scaled to a preset width
+// (GET /api/images/:size/*path, e.g. /api/images/w342/abc.jpg)
+func (h *ImageHandler) GetImage(c *gin.Context) {
+	size, path := c.Param("size"), c.Param("path")
+	img, err := h.images.Get(c.Request.Context(), size, path)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnknownImageSize):
+			sizes := make([]string, 0, len(usecase.ImageSizes))
+			for name := range usecase.ImageSizes {
+				sizes = append(sizes, name)
+			}
+			slices.Sort(sizes)
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Unknown image size", "sizes": sizes})
+		case errors.Is(err, usecase.ErrInvalidImagePath):
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid image path"})
+		case errors.Is(err, usecase.ErrUnsupportedImage):
+			log.Printf("Error resizing image %s%s: %v", size, path, err)
+			c.JSON(stdhttp.StatusUnprocessableEntity, gin.H{"error": "Image can't be resized"})
+		default:
+			log.Printf("Error fetching image %s%s: %v", size, path, err)
+			c.JSON(stdhttp.StatusBadGateway, gin.H{"error": "Failed to fetch image"})
+		}
+		return
+	}
+	if img == nil {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Image not found"})
+		return
+	}
+
+	// Image paths name their content, so an image never changes
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("ETag", img.ETag)
+	if etagMatches(c.GetHeader("If-None-Match"), img.ETag) {
+		c.Status(stdhttp.StatusNotModified)
+		return
+	}
+	c.Data(stdhttp.StatusOK, img.ContentType, img.Data)
+}
@@ -0,0 +1,79 @@
+package deliveryhttp
+
+import (
+	"io"
+	"log"
+	stdhttp "net/http"
+
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBytes caps uploaded export files
+const maxImportBytes = 20 << 20
+
+type ImportHandler struct {
+	importer *usecase.Importer
+}
+
+// NewImportHandler creates the handler; importer is nil when TMDB isn't configured
+func NewImportHandler(importer *usecase.Importer) *ImportHandler {
+	return &ImportHandler{importer: importer}
+}
+
+// Import accepts a Letterboxd ZIP/CSV or IMDb CSV export as the multipart
+// "file" field. Optional form fields: source (letterboxd|imdb) and dry_run.
+func (h *ImportHandler) Import(c *gin.Context) {
+	// Cap the body before anything parses the multipart form
+	c.Request.Body = stdhttp.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	if h.importer == nil {
+		c.JSON(stdhttp.StatusServiceUnavailable, gin.H{"error": "Import is not available"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	source := c.PostForm("source")
+	if source != "" && source != usecase.ImportLetterboxd && source != usecase.ImportIMDb {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "source must be letterboxd or imdb"})
+		return
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "file is required (max 20MB)"})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Could not read file"})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Could not read file"})
+		return
+	}
+
+	dryRun := c.PostForm("dry_run") == "true" || c.PostForm("dry_run") == "1"
+	report, err := h.importer.Import(userID, fh.Filename, data, source, dryRun)
+	if err != nil {
+		if usecase.IsImportInputError(err) {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Error importing %s: %v", fh.Filename, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Import failed"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, report)
+}
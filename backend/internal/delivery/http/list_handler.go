@@ -0,0 +1,562 @@
+package deliveryhttp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// inviteTTL is how long an invite token can be accepted
+const inviteTTL = 7 * 24 * time.Hour
+
+type ListRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type InviteRequest struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type AcceptInviteRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type MemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type SharingRequest struct {
+	Visibility string `json:"visibility" binding:"required,oneof=private public"`
+	ShareNotes *bool  `json:"share_notes"` // unchanged when omitted
+}
+
+// ListHandler manages shared watchlists, their members and invites. Items on
+// a shared list go through WatchlistHandler with ?list_id=.
+type ListHandler struct {
+	lists repository.ListRepo
+}
+
+func NewListHandler(lists repository.ListRepo) *ListHandler {
+	return &ListHandler{lists: lists}
+}
+
+// CreateList creates a named list owned by the caller (POST /api/lists)
+func (h *ListHandler) CreateList(c *gin.Context) {
+	var req ListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "List name is required"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list := &domain.Watchlist{OwnerID: userID, Name: name}
+	if err := h.lists.CreateList(list); err != nil {
+		log.Printf("Error creating list: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to create list"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusCreated, domain.ListAccess{Watchlist: *list, Role: domain.RoleOwner})
+}
+
+// GetLists returns the lists the caller owns or has joined (GET /api/lists)
+func (h *ListHandler) GetLists(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	lists, err := h.lists.ListsForUser(userID)
+	if err != nil {
+		log.Printf("Error fetching lists: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch lists"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"lists": lists,
+		"count": len(lists),
+	})
+}
+
+// GetList returns a list with its members (GET /api/lists/:id)
+func (h *ListHandler) GetList(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, role, ok := h.authorize(c, userID, domain.RoleViewer)
+	if !ok {
+		return
+	}
+	full, err := h.lists.GetList(list.ID)
+	if err != nil || full == nil {
+		log.Printf("Error fetching list %d: %v", list.ID, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch list"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, domain.ListAccess{Watchlist: *full, Role: role})
+}
+
+// RenameList changes a list's name; owner only (PATCH /api/lists/:id)
+func (h *ListHandler) RenameList(c *gin.Context) {
+	var req ListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "List name is required"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, _, ok := h.authorize(c, userID, domain.RoleOwner)
+	if !ok {
+		return
+	}
+	if err := h.lists.RenameList(list.ID, name); err != nil {
+		log.Printf("Error renaming list %d: %v", list.ID, err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to rename list",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+		Message: "List renamed",
+		Success: true,
+	})
+}
+
+// DeleteList deletes a list and everything on it; owner only (DELETE /api/lists/:id)
+func (h *ListHandler) DeleteList(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, _, ok := h.authorize(c, userID, domain.RoleOwner)
+	if !ok {
+		return
+	}
+	if err := h.lists.DeleteList(list.ID); err != nil {
+		log.Printf("Error deleting list %d: %v", list.ID, err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to delete list",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+		Message: "List deleted",
+		Success: true,
+	})
+}
+
+// GetSharing returns a list's sharing settings; owner only
+// (GET /api/lists/:id/sharing). List ID 0 is the caller's personal watchlist.
+func (h *ListHandler) GetSharing(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.sharingList(c, userID)
+	if !ok {
+		return
+	}
+	writeSharing(c, list.Visibility, list.PublicSlug, list.ShareNotes)
+}
+
+// SetSharing makes a list public or private; owner only
+// (PUT /api/lists/:id/sharing). Making a list public creates its slug if it
+// has none; making it private keeps the slug so the link works again later.
+// Items' notes and tags stay off the public link unless share_notes is set.
+// List ID 0 is the caller's personal watchlist.
+func (h *ListHandler) SetSharing(c *gin.Context) {
+	var req SharingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.sharingList(c, userID)
+	if !ok {
+		return
+	}
+	slug := list.PublicSlug
+	if req.Visibility == domain.VisibilityPublic && slug == nil {
+		var err error
+		if slug, err = newPublicSlug(); err != nil {
+			log.Printf("Error generating public slug: %v", err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to update sharing"})
+			return
+		}
+	}
+	shareNotes := list.ShareNotes
+	if req.ShareNotes != nil {
+		shareNotes = *req.ShareNotes
+	}
+	h.saveSharing(c, list, req.Visibility, slug, shareNotes)
+}
+
+// RotatePublicSlug replaces a list's public link, breaking the old one;
+// owner only (POST /api/lists/:id/sharing/rotate)
+func (h *ListHandler) RotatePublicSlug(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.sharingList(c, userID)
+	if !ok {
+		return
+	}
+	slug, err := newPublicSlug()
+	if err != nil {
+		log.Printf("Error generating public slug: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to update sharing"})
+		return
+	}
+	h.saveSharing(c, list, list.Visibility, slug, list.ShareNotes)
+}
+
+// RevokePublicSlug makes a list private and deletes its public link;
+// owner only (DELETE /api/lists/:id/sharing)
+func (h *ListHandler) RevokePublicSlug(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.sharingList(c, userID)
+	if !ok {
+		return
+	}
+	h.saveSharing(c, list, domain.VisibilityPrivate, nil, false)
+}
+
+// sharingList loads the :id list for its owner's sharing settings. List ID 0
+// is the caller's personal watchlist, which only they can own.
+func (h *ListHandler) sharingList(c *gin.Context, userID uint) (*domain.Watchlist, bool) {
+	if c.Param("id") != "0" {
+		list, _, ok := h.authorize(c, userID, domain.RoleOwner)
+		return list, ok
+	}
+	list, err := h.lists.GetPersonalSharing(userID)
+	if err != nil {
+		log.Printf("Error loading sharing for user %d: %v", userID, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to load list"})
+		return nil, false
+	}
+	return list, true
+}
+
+// saveSharing stores a list's sharing settings and writes them back
+func (h *ListHandler) saveSharing(c *gin.Context, list *domain.Watchlist, visibility string, slug *string, shareNotes bool) {
+	var err error
+	if list.ID == 0 {
+		err = h.lists.SetPersonalSharing(list.OwnerID, visibility, slug, shareNotes)
+	} else {
+		err = h.lists.SetListSharing(list.ID, visibility, slug, shareNotes)
+	}
+	if err != nil {
+		log.Printf("Error updating sharing for list %d of user %d: %v", list.ID, list.OwnerID, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to update sharing"})
+		return
+	}
+	writeSharing(c, visibility, slug, shareNotes)
+}
+
+// writeSharing writes a list's sharing settings with its public path
+func writeSharing(c *gin.Context, visibility string, slug *string, shareNotes bool) {
+	resp := gin.H{"visibility": visibility, "share_notes": shareNotes, "public_slug": nil, "public_path": nil}
+	if slug != nil {
+		resp["public_slug"] = *slug
+		resp["public_path"] = "/api/public/lists/" + *slug
+	}
+	c.JSON(stdhttp.StatusOK, resp)
+}
+
+// CreateInvite issues a one-time invite token; owner only
+// (POST /api/lists/:id/invites). The token is only ever returned here.
+func (h *ListHandler) CreateInvite(c *gin.Context) {
+	var req InviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, _, ok := h.authorize(c, userID, domain.RoleOwner)
+	if !ok {
+		return
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Printf("Error generating invite token: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to create invite"})
+		return
+	}
+	token := hex.EncodeToString(raw)
+	invite := &domain.WatchlistInvite{
+		WatchlistID: list.ID,
+		TokenHash:   hashInviteToken(token),
+		Role:        req.Role,
+		InvitedByID: userID,
+		ExpiresAt:   time.Now().Add(inviteTTL),
+	}
+	if err := h.lists.CreateInvite(invite); err != nil {
+		log.Printf("Error creating invite for list %d: %v", list.ID, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to create invite"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusCreated, gin.H{
+		"token":      token,
+		"list_id":    list.ID,
+		"role":       invite.Role,
+		"expires_at": invite.ExpiresAt,
+	})
+}
+
+// AcceptInvite joins the list an invite token belongs to (POST /api/invites/accept)
+func (h *ListHandler) AcceptInvite(c *gin.Context) {
+	var req AcceptInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	access, err := h.lists.AcceptInvite(hashInviteToken(strings.TrimSpace(req.Token)), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Invite is invalid, expired or already used"})
+			return
+		}
+		log.Printf("Error accepting invite: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to accept invite"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, access)
+}
+
+// UpdateMember changes a member's role; owner only
+// (PATCH /api/lists/:id/members/:user_id)
+func (h *ListHandler) UpdateMember(c *gin.Context) {
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	var req MemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, _, ok := h.authorize(c, userID, domain.RoleOwner)
+	if !ok {
+		return
+	}
+	if err := h.lists.SetMemberRole(list.ID, uint(memberID), req.Role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(stdhttp.StatusNotFound, gin.H{"error": "User is not a member of this list"})
+			return
+		}
+		log.Printf("Error updating member of list %d: %v", list.ID, err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to update member",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+		Message: "Member updated",
+		Success: true,
+	})
+}
+
+// RemoveMember removes a member from a list. The owner can remove anyone;
+// other members can only leave (DELETE /api/lists/:id/members/:user_id).
+func (h *ListHandler) RemoveMember(c *gin.Context) {
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	need := domain.RoleOwner
+	if uint(memberID) == userID {
+		need = domain.RoleViewer
+	}
+	list, _, ok := h.authorize(c, userID, need)
+	if !ok {
+		return
+	}
+	removed, err := h.lists.RemoveMember(list.ID, uint(memberID))
+	if err != nil {
+		log.Printf("Error removing member from list %d: %v", list.ID, err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to remove member",
+			Success: false,
+		})
+		return
+	}
+	if !removed {
+		c.JSON(stdhttp.StatusNotFound, WatchlistResponse{
+			Message: "User is not a member of this list",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+		Message: "Member removed",
+		Success: true,
+	})
+}
+
+// authorize loads the :id list and checks the caller holds at least need on
+// it. On failure the response has been written and ok is false.
+func (h *ListHandler) authorize(c *gin.Context, userID uint, need string) (*domain.Watchlist, string, bool) {
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid list ID"})
+		return nil, "", false
+	}
+	return checkListAccess(c, h.lists, uint(listID), userID, need)
+}
+
+// checkListAccess loads a list and the user's role on it, writing a 404 if
+// they aren't a member and a 403 if their role is below need
+func checkListAccess(c *gin.Context, lists repository.ListRepo, listID, userID uint, need string) (*domain.Watchlist, string, bool) {
+	list, role, err := lists.GetListRole(listID, userID)
+	if err != nil {
+		log.Printf("Error loading list %d: %v", listID, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to load list"})
+		return nil, "", false
+	}
+	// Non-members can't tell a private list from a missing one
+	if list == nil || role == "" {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "List not found"})
+		return nil, "", false
+	}
+	if !domain.RoleAtLeast(role, need) {
+		c.JSON(stdhttp.StatusForbidden, gin.H{"error": "You need " + need + " access to this list"})
+		return nil, "", false
+	}
+	return list, role, true
+}
+
+// newPublicSlug returns an unguessable 16-character URL-safe slug
+func newPublicSlug() (*string, error) {
+	raw := make([]byte, 12)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	slug := base64.RawURLEncoding.EncodeToString(raw)
+	return &slug, nil
+}
+
+// hashInviteToken is what invites are stored and looked up by
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,94 @@
+package deliveryhttp_test
+
+import (
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	deliveryhttp "github.com/HMZ-H/moviemate/internal/delivery/http"
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// memPersonalSharing keeps personal list sharing in memory; other ListRepo
+// methods aren't implemented
+type memPersonalSharing struct {
+	repository.ListRepo
+	lists map[uint]domain.Watchlist // by owner
+}
+
+func (m *memPersonalSharing) GetPersonalSharing(userID uint) (*domain.Watchlist, error) {
+	list, ok := m.lists[userID]
+	if !ok {
+		list = domain.Watchlist{OwnerID: userID, Name: domain.PersonalListName, Visibility: domain.VisibilityPrivate}
+	}
+	return &list, nil
+}
+
+func (m *memPersonalSharing) SetPersonalSharing(userID uint, visibility string, slug *string, shareNotes bool) error {
+	m.lists[userID] = domain.Watchlist{OwnerID: userID, Name: domain.PersonalListName, Visibility: visibility, PublicSlug: slug, ShareNotes: shareNotes}
+	return nil
+}
+
+type sharingResponse struct {
+	Visibility string  `json:"visibility"`
+	ShareNotes bool    `json:"share_notes"`
+	PublicSlug *string `json:"public_slug"`
+	PublicPath *string `json:"public_path"`
+}
+
+func TestPersonalListSharing(t *testing.T) {
+	repo := &memPersonalSharing{lists: map[uint]domain.Watchlist{}}
+	h := deliveryhttp.NewListHandler(repo)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", uint(7)) })
+	r.GET("/api/lists/:id/sharing", h.GetSharing)
+	r.PUT("/api/lists/:id/sharing", h.SetSharing)
+	r.POST("/api/lists/:id/sharing/rotate", h.RotatePublicSlug)
+	r.DELETE("/api/lists/:id/sharing", h.RevokePublicSlug)
+
+	do := func(method, body string) sharingResponse {
+		t.Helper()
+		target := "/api/lists/0/sharing"
+		if method == stdhttp.MethodPost {
+			target += "/rotate"
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		if w.Code != stdhttp.StatusOK {
+			t.Fatalf("%s %s = %d: %s", method, target, w.Code, w.Body)
+		}
+		var resp sharingResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	if got := do(stdhttp.MethodGet, ""); got.Visibility != domain.VisibilityPrivate || got.PublicSlug != nil {
+		t.Errorf("unshared list = %+v, want private without a slug", got)
+	}
+	public := do(stdhttp.MethodPut, `{"visibility": "public", "share_notes": true}`)
+	if public.Visibility != domain.VisibilityPublic || public.PublicSlug == nil || !public.ShareNotes ||
+		*public.PublicPath != "/api/public/lists/"+*public.PublicSlug {
+		t.Fatalf("shared list = %+v, want public with a slug and notes", public)
+	}
+	// Going private and public again keeps the link
+	do(stdhttp.MethodPut, `{"visibility": "private"}`)
+	if again := do(stdhttp.MethodPut, `{"visibility": "public"}`); again.PublicSlug == nil || *again.PublicSlug != *public.PublicSlug || !again.ShareNotes {
+		t.Errorf("re-shared list = %+v, want slug %s with notes", again, *public.PublicSlug)
+	}
+	if rotated := do(stdhttp.MethodPost, ""); rotated.PublicSlug == nil || *rotated.PublicSlug == *public.PublicSlug {
+		t.Errorf("rotated list = %+v, want a new slug", rotated)
+	}
+	if revoked := do(stdhttp.MethodDelete, ""); revoked.Visibility != domain.VisibilityPrivate || revoked.PublicSlug != nil || revoked.ShareNotes {
+		t.Errorf("revoked list = %+v, want private without a slug", revoked)
+	}
+	if stored := repo.lists[7]; stored.PublicSlug != nil {
+		t.Errorf("stored slug = %v after revoking", *stored.PublicSlug)
+	}
+}
@@ -0,0 +1,125 @@
+package deliveryhttp
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	stdhttp "net/http"
+	"strconv"
+
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// Page size bounds for GET /api/notifications
+const (
+	defaultNotificationLimit = 50
+	maxNotificationLimit     = 200
+)
+
+type NotificationHandler struct {
+	notifications repository.NotificationRepo
+}
+
+func NewNotificationHandler(notifications repository.NotificationRepo) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// GetNotifications returns the newest notifications first
+// (GET /api/notifications?unread=true&limit=)
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultNotificationLimit)))
+	if err != nil || limit <= 0 || limit > maxNotificationLimit {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxNotificationLimit)})
+		return
+	}
+	unreadOnly := c.Query("unread") == "true"
+
+	notes, err := h.notifications.ListNotifications(userID, unreadOnly, limit)
+	if err != nil {
+		log.Printf("Error fetching notifications: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+	unread, err := h.notifications.CountUnreadNotifications(userID)
+	if err != nil {
+		log.Printf("Error counting notifications: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"notifications": notes,
+		"count":         len(notes),
+		"unread":        unread,
+	})
+}
+
+// MarkNotificationRead marks one notification read (POST /api/notifications/:id/read)
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	if err := h.notifications.MarkNotificationRead(userID, uint(id)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Notification not found"})
+			return
+		}
+		log.Printf("Error marking notification read: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to update notification",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+		Message: "Notification marked read",
+		Success: true,
+	})
+}
+
+// MarkAllNotificationsRead marks every notification read (POST /api/notifications/read-all)
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	marked, err := h.notifications.MarkAllNotificationsRead(userID)
+	if err != nil {
+		log.Printf("Error marking notifications read: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to update notifications",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"marked":  marked,
+		"success": true,
+	})
+}
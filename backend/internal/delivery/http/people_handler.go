@@ -0,0 +1,82 @@
+package deliveryhttp
+
+import (
+	"log"
+	stdhttp "net/http"
+	"strconv"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// PeopleHandler serves cast and crew from the stored catalog
+type PeopleHandler struct {
+	people repository.PersonRepo
+}
+
+func NewPeopleHandler(people repository.PersonRepo) *PeopleHandler {
+	return &PeopleHandler{people: people}
+}
+
+// FilmographyCredit is a stored movie a person is credited on, as cast
+// (character) or crew (department and job)
+type FilmographyCredit struct {
+	MovieResponse
+	Character  string `json:"character,omitempty"`
+	Department string `json:"department,omitempty"`
+	Job        string `json:"job,omitempty"`
+}
+
+// PersonResponse is a person with their filmography, newest first
+type PersonResponse struct {
+	domain.Person
+	Cast []FilmographyCredit `json:"cast"`
+	Crew []FilmographyCredit `json:"crew"`
+}
+
+// GetPerson returns a person and the stored movies they're credited on
+// (GET /api/people/:id)
+func (h *PeopleHandler) GetPerson(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+	person, err := h.people.GetPerson(uint(id))
+	if err != nil {
+		log.Printf("Error loading person %d: %v", id, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to load person"})
+		return
+	}
+	if person == nil {
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+	credits, err := h.people.ListPersonCredits(person.ID)
+	if err != nil {
+		log.Printf("Error loading credits of person %d: %v", id, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to load person"})
+		return
+	}
+
+	resp := PersonResponse{Person: *person, Cast: []FilmographyCredit{}, Crew: []FilmographyCredit{}}
+	for _, credit := range credits {
+		if credit.Movie == nil {
+			continue
+		}
+		fc := FilmographyCredit{
+			MovieResponse: newMovieResponse(*credit.Movie),
+			Character:     credit.Character,
+			Department:    credit.Department,
+			Job:           credit.Job,
+		}
+		if credit.Role == domain.CreditCast {
+			resp.Cast = append(resp.Cast, fc)
+		} else {
+			resp.Crew = append(resp.Crew, fc)
+		}
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(stdhttp.StatusOK, resp)
+}
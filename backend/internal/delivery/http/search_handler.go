@@ -0,0 +1,230 @@
+package deliveryhttp
+
+import (
+	"fmt"
+	"log"
+	stdhttp "net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// Search result page sizes
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+// Autocomplete bounds: prefixes shorter than two characters match too much
+// to be useful, and a dropdown needs few rows
+const (
+	minSuggestPrefix    = 2
+	maxSuggestPrefix    = 100
+	defaultSuggestLimit = 8
+	maxSuggestLimit     = 20
+)
+
+// MovieResponse is a stored movie
+type MovieResponse struct {
+	ID            uint           `json:"id"`
+	MediaType     string         `json:"media_type"`
+	TMDBID        *uint          `json:"tmdb_id,omitempty"`
+	IMDbID        *string        `json:"imdb_id,omitempty"`
+	Title         string         `json:"title"`
+	OriginalTitle string         `json:"original_title,omitempty"`
+	Description   string         `json:"description,omitempty"`
+	Year          int            `json:"year,omitempty"`
+	Genres        []domain.Genre `json:"genres"`
+	CastNames     []string       `json:"cast_names,omitempty"`
+	Popularity    float64        `json:"popularity"`
+}
+
+func newMovieResponse(m domain.Movie) MovieResponse {
+	genres := m.Genres
+	if genres == nil {
+		genres = []domain.Genre{}
+	}
+	return MovieResponse{
+		ID:            m.ID,
+		MediaType:     m.MediaType,
+		TMDBID:        m.TMDBID,
+		IMDbID:        m.IMDbID,
+		Title:         m.Title,
+		OriginalTitle: m.OriginalTitle,
+		Description:   m.Description,
+		Year:          m.Year,
+		Genres:        genres,
+		CastNames:     m.CastNames,
+		Popularity:    m.Popularity,
+	}
+}
+
+// SearchResult is a search hit. TitleHighlight and Snippet are HTML-escaped
+// with matching words wrapped in <mark>.
+type SearchResult struct {
+	MovieResponse
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+// SearchResponse is a page of results. Fuzzy is set when nothing matched
+// the words exactly and the results are titles spelled like the query.
+type SearchResponse struct {
+	Results      []SearchResult `json:"results"`
+	Fuzzy        bool           `json:"fuzzy"`
+	Page         int            `json:"page"`
+	TotalResults int64          `json:"total_results"`
+	TotalPages   int64          `json:"total_pages"`
+}
+
+// SearchHandler serves title lookups and full-text search over the stored catalog
+type SearchHandler struct {
+	movies        *usecase.MovieUsecase
+	suggestBudget time.Duration
+}
+
+// NewSearchHandler creates the handler; suggestBudget bounds how long
+// autocomplete waits on the database
+func NewSearchHandler(movies *usecase.MovieUsecase, suggestBudget time.Duration) *SearchHandler {
+	return &SearchHandler{movies: movies, suggestBudget: suggestBudget}
+}
+
+// SearchMovies finds movies by title, original title, cast and overview
+// (GET /api/search?q=&year=&genres=28,12&page=&limit=)
+func (h *SearchHandler) SearchMovies(c *gin.Context) {
+	q := domain.MovieSearchQuery{Query: strings.TrimSpace(c.Query("q")), Limit: defaultSearchLimit}
+	if q.Query == "" || len(q.Query) > maxSearchQuery {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("q must be 1 to %d characters", maxSearchQuery)})
+		return
+	}
+	if year := c.Query("year"); year != "" {
+		y, err := strconv.Atoi(year)
+		if err != nil || y < 1870 || y > 2100 {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+		q.Year = y
+	}
+	if genres := c.Query("genres"); genres != "" {
+		for _, g := range strings.Split(genres, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(g), 10, 32)
+			if err != nil || id == 0 {
+				c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "genres must be comma-separated genre IDs"})
+				return
+			}
+			q.GenreIDs = append(q.GenreIDs, uint(id))
+		}
+		// The repository matches movies with as many genres as IDs given
+		slices.Sort(q.GenreIDs)
+		q.GenreIDs = slices.Compact(q.GenreIDs)
+	}
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 || l > maxSearchLimit {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit)})
+			return
+		}
+		q.Limit = l
+	}
+	page, ok := catalogPage(c)
+	if !ok {
+		return
+	}
+	q.Offset = (page - 1) * q.Limit
+
+	hits, total, fuzzy, err := h.movies.SearchMovies(q)
+	if err != nil {
+		log.Printf("Error searching movies: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to search movies"})
+		return
+	}
+	results := make([]SearchResult, len(hits))
+	for i, hit := range hits {
+		results[i] = SearchResult{
+			MovieResponse:  newMovieResponse(hit.Movie),
+			Rank:           hit.Rank,
+			TitleHighlight: hit.TitleHighlight,
+			Snippet:        hit.Snippet,
+		}
+	}
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(stdhttp.StatusOK, SearchResponse{
+		Results:      results,
+		Fuzzy:        fuzzy,
+		Page:         page,
+		TotalResults: total,
+		TotalPages:   (total + int64(q.Limit) - 1) / int64(q.Limit),
+	})
+}
+
+// FindMovies looks up stored movies by exact title, ignoring case, and
+// optionally year, e.g. to tell remakes apart (GET /api/movies?title=&year=)
+func (h *SearchHandler) FindMovies(c *gin.Context) {
+	title := strings.TrimSpace(c.Query("title"))
+	if title == "" || len(title) > maxSearchQuery {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("title must be 1 to %d characters", maxSearchQuery)})
+		return
+	}
+	year := 0
+	if raw := c.Query("year"); raw != "" {
+		y, err := strconv.Atoi(raw)
+		if err != nil || y < 1870 || y > 2100 {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+		year = y
+	}
+
+	movies, err := h.movies.FindMovies(title, year)
+	if err != nil {
+		log.Printf("Error finding movies titled %q: %v", title, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to find movies"})
+		return
+	}
+	results := make([]MovieResponse, len(movies))
+	for i, m := range movies {
+		results[i] = newMovieResponse(m)
+	}
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(stdhttp.StatusOK, gin.H{"results": results})
+}
+
+// Suggest completes a search box prefix with titles and people
+// (GET /api/search/suggest?q=&limit=)
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	prefix := strings.TrimSpace(c.Query("q"))
+	if n := utf8.RuneCountInString(prefix); n < minSuggestPrefix || n > maxSuggestPrefix {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("q must be %d to %d characters", minSuggestPrefix, maxSuggestPrefix)})
+		return
+	}
+	limit := defaultSuggestLimit
+	if raw := c.Query("limit"); raw != "" {
+		l, err := strconv.Atoi(raw)
+		if err != nil || l < 1 || l > maxSuggestLimit {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxSuggestLimit)})
+			return
+		}
+		limit = l
+	}
+
+	suggestions, err := h.movies.Suggest(c.Request.Context(), prefix, limit, h.suggestBudget)
+	if err != nil {
+		log.Printf("Error suggesting %q: %v", prefix, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+		return
+	}
+	// A partial answer shouldn't stick in caches
+	if suggestions.Partial {
+		c.Header("Cache-Control", "no-store")
+	} else {
+		c.Header("Cache-Control", "public, max-age=300")
+	}
+	c.JSON(stdhttp.StatusOK, suggestions)
+}
@@ -0,0 +1,190 @@
+package deliveryhttp
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	stdhttp "net/http"
+	"strconv"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/HMZ-H/moviemate/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// Show count bounds for GET /api/tv/continue-watching
+const (
+	defaultContinueWatchingLimit = 20
+	maxContinueWatchingLimit     = 50
+)
+
+type TVHandler struct {
+	progress *usecase.TVProgress
+}
+
+func NewTVHandler(progress *usecase.TVProgress) *TVHandler {
+	return &TVHandler{progress: progress}
+}
+
+// GetShowProgress returns every season of a show with the episodes the user
+// has watched and what's next up (GET /api/tv/:show_id/progress)
+func (h *TVHandler) GetShowProgress(c *gin.Context) {
+	showID, err := strconv.ParseUint(c.Param("show_id"), 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid show ID"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	progress, err := h.progress.Progress(userID, uint(showID), time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Show not found"})
+			return
+		}
+		log.Printf("Error fetching show progress: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch progress"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, progress)
+}
+
+// GetContinueWatching returns started shows with an episode to watch next,
+// most recently watched first (GET /api/tv/continue-watching?limit=)
+func (h *TVHandler) GetContinueWatching(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultContinueWatchingLimit)))
+	if err != nil || limit <= 0 || limit > maxContinueWatchingLimit {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxContinueWatchingLimit)})
+		return
+	}
+
+	shows, err := h.progress.ContinueWatching(userID, limit, time.Now())
+	if err != nil {
+		log.Printf("Error fetching continue watching: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch continue watching"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"shows": shows,
+		"count": len(shows),
+	})
+}
+
+// MarkEpisodeWatched (POST) and UnmarkEpisodeWatched (DELETE) serve
+// /api/tv/:show_id/seasons/:season/episodes/:episode/watched
+func (h *TVHandler) MarkEpisodeWatched(c *gin.Context)   { h.markEpisode(c, true) }
+func (h *TVHandler) UnmarkEpisodeWatched(c *gin.Context) { h.markEpisode(c, false) }
+
+func (h *TVHandler) markEpisode(c *gin.Context, watched bool) {
+	showID, season, ok := parseShowSeason(c)
+	if !ok {
+		return
+	}
+	episode, err := strconv.Atoi(c.Param("episode"))
+	if err != nil || episode < 0 {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid episode number"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	if err := h.progress.MarkEpisode(userID, showID, season, episode, watched); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Episode not found"})
+			return
+		}
+		log.Printf("Error updating episode progress: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to update progress",
+			Success: false,
+		})
+		return
+	}
+
+	message := "Episode marked watched"
+	if !watched {
+		message = "Episode marked unwatched"
+	}
+	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+		Message: message,
+		Success: true,
+	})
+}
+
+// MarkSeasonWatched (POST) marks every aired episode of a season watched and
+// UnmarkSeasonWatched (DELETE) clears the season (/api/tv/:show_id/seasons/:season/watched)
+func (h *TVHandler) MarkSeasonWatched(c *gin.Context)   { h.markSeason(c, true) }
+func (h *TVHandler) UnmarkSeasonWatched(c *gin.Context) { h.markSeason(c, false) }
+
+func (h *TVHandler) markSeason(c *gin.Context, watched bool) {
+	showID, season, ok := parseShowSeason(c)
+	if !ok {
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	changed, err := h.progress.MarkSeason(userID, showID, season, watched, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Season not found"})
+			return
+		}
+		log.Printf("Error updating season progress: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to update progress",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"changed": changed,
+		"success": true,
+	})
+}
+
+// parseShowSeason reads the :show_id and :season path parameters
+func parseShowSeason(c *gin.Context) (uint, int, bool) {
+	showID, err := strconv.ParseUint(c.Param("show_id"), 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid show ID"})
+		return 0, 0, false
+	}
+	season, err := strconv.Atoi(c.Param("season"))
+	if err != nil || season < 0 {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid season number"})
+		return 0, 0, false
+	}
+	return uint(showID), season, true
+}
@@ -0,0 +1,169 @@
+package deliveryhttp
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// Page size bounds for GET /api/watchlist/changes
+const (
+	defaultChangesLimit = 100
+	maxChangesLimit     = 500
+)
+
+// WatchlistChangeResponse is a change log entry. Item holds the title's
+// current state for added and updated entries if it's still on the list.
+type WatchlistChangeResponse struct {
+	domain.WatchlistChange
+	Item *WatchlistItemResponse `json:"item,omitempty"`
+}
+
+// GetWatchlistChanges returns changes after version since, oldest first
+// (GET /api/watchlist/changes?since=). A client that is ahead of the server,
+// e.g. after its list was deleted and recreated, gets reset=true and should
+// re-fetch the whole list.
+func (h *WatchlistHandler) GetWatchlistChanges(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.resolveList(c, userID, domain.RoleViewer)
+	if !ok {
+		return
+	}
+
+	since, err := strconv.ParseInt(c.DefaultQuery("since", "0"), 10, 64)
+	if err != nil || since < 0 {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "since must be a non-negative integer"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultChangesLimit)))
+	if err != nil || limit <= 0 || limit > maxChangesLimit {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxChangesLimit)})
+		return
+	}
+
+	current, err := h.watchlistRepo.GetWatchlistVersion(list)
+	if err != nil {
+		log.Printf("Error fetching watchlist version: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch changes"})
+		return
+	}
+	if since > current {
+		c.JSON(stdhttp.StatusOK, gin.H{
+			"changes":  []WatchlistChangeResponse{},
+			"version":  current,
+			"has_more": false,
+			"reset":    true,
+		})
+		return
+	}
+
+	changes, err := h.watchlistRepo.ListWatchlistChanges(list, since, limit+1)
+	if err != nil {
+		log.Printf("Error fetching watchlist changes: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch changes"})
+		return
+	}
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+
+	resp, err := h.hydrateChanges(list, changes)
+	if err != nil {
+		log.Printf("Error loading changed watchlist items: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch changes"})
+		return
+	}
+	version := since
+	if len(changes) > 0 {
+		version = changes[len(changes)-1].Version
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"changes":  resp,
+		"version":  version,
+		"has_more": hasMore,
+		"reset":    false,
+	})
+}
+
+// hydrateChanges attaches current item state to added and updated entries
+func (h *WatchlistHandler) hydrateChanges(list domain.ListRef, changes []domain.WatchlistChange) ([]WatchlistChangeResponse, error) {
+	var ids []uint
+	seen := map[uint]bool{}
+	for _, ch := range changes {
+		if ch.Kind != domain.ChangeRemoved && !seen[ch.MovieID] {
+			seen[ch.MovieID] = true
+			ids = append(ids, ch.MovieID)
+		}
+	}
+	items, err := h.watchlistRepo.GetWatchlistItems(list, ids)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := h.metadata.Lookup(ids)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[uint]*WatchlistItemResponse, len(items))
+	for _, item := range items {
+		r := newWatchlistItemResponse(item, meta[item.MovieID])
+		current[item.MovieID] = &r
+	}
+
+	resp := make([]WatchlistChangeResponse, len(changes))
+	for i, ch := range changes {
+		resp[i] = WatchlistChangeResponse{WatchlistChange: ch}
+		if ch.Kind != domain.ChangeRemoved {
+			resp[i].Item = current[ch.MovieID]
+		}
+	}
+	return resp, nil
+}
+
+// watchlistETag identifies a response by the list, its version and a key
+// built from the query string and anything else the body depends on, such as
+// a metadata stamp. It's weak because equal tags needn't be byte-identical.
+func watchlistETag(list domain.ListRef, version int64, rawQuery string) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%d?%s", list.OwnerID, list.ListID, rawQuery)
+	return fmt.Sprintf(`W/"%d-%x"`, version, h.Sum64())
+}
+
+// metadataStamp fingerprints the list's cached metadata for an ETag key.
+// Responses that show or sort by metadata change when it's fetched or
+// refreshed, which doesn't bump the list version.
+func (h *WatchlistHandler) metadataStamp(list domain.ListRef) (string, error) {
+	hydrated, latest, err := h.watchlistRepo.GetWatchlistMetadataStamp(list)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("&meta=%d@%d", hydrated, latest.UnixNano()), nil
+}
+
+// etagMatches reports whether an If-None-Match header matches etag
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package deliveryhttp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// exportPageSize is how many items are loaded and written per flush
+const exportPageSize = 200
+
+// watchlistExporter writes one export format
+type watchlistExporter interface {
+	begin() error
+	write(item WatchlistItemResponse) error
+	end() error
+}
+
+// ExportWatchlist streams a whole watchlist with metadata
+// (GET /api/watchlist/export?format=csv|json|letterboxd)
+func (h *WatchlistHandler) ExportWatchlist(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.resolveList(c, userID, domain.RoleViewer)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	var exp watchlistExporter
+	var contentType, filename string
+	switch format {
+	case "csv":
+		exp = &csvExporter{w: csv.NewWriter(c.Writer)}
+		contentType, filename = "text/csv; charset=utf-8", "moviemate-watchlist.csv"
+	case "json":
+		exp = &jsonExporter{c: c}
+		contentType, filename = "application/json; charset=utf-8", "moviemate-watchlist.json"
+	case "letterboxd":
+		exp = &letterboxdExporter{w: csv.NewWriter(c.Writer)}
+		contentType, filename = "text/csv; charset=utf-8", "moviemate-letterboxd.csv"
+	default:
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "format must be csv, json or letterboxd"})
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(stdhttp.StatusOK)
+
+	// Headers are sent by now, so failures can only be logged
+	if err := h.streamExport(c, list, exp); err != nil {
+		log.Printf("Error exporting watchlist for user %d: %v", userID, err)
+	}
+}
+
+func (h *WatchlistHandler) streamExport(c *gin.Context, list domain.ListRef, exp watchlistExporter) error {
+	if err := exp.begin(); err != nil {
+		return err
+	}
+	query := domain.WatchlistQuery{List: list, Sort: domain.SortRank, Limit: exportPageSize}
+	for {
+		page, err := h.watchlistRepo.ListWatchlistPage(query)
+		if err != nil {
+			return err
+		}
+		ids := make([]uint, len(page.Items))
+		for i, item := range page.Items {
+			ids[i] = item.MovieID
+		}
+		meta, err := h.metadata.Lookup(ids)
+		if err != nil {
+			return err
+		}
+		for _, item := range page.Items {
+			if err := exp.write(newWatchlistItemResponse(item, meta[item.MovieID])); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	if err := exp.end(); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// csvExporter writes every field we store, one row per item
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) begin() error {
+	return e.w.Write([]string{"tmdb_id", "media_type", "imdb_id", "title", "year", "runtime", "rating",
+		"genres", "priority", "tags", "notes", "added_at"})
+}
+
+func (e *csvExporter) write(it WatchlistItemResponse) error {
+	if err := e.w.Write([]string{
+		strconv.FormatUint(uint64(it.MovieID), 10),
+		it.MediaType,
+		it.IMDbID,
+		it.Title,
+		optionalInt(it.Year),
+		optionalInt(it.Runtime),
+		strconv.FormatFloat(it.Rating, 'f', -1, 64),
+		strings.Join(it.Genres, ", "),
+		it.Priority,
+		strings.Join(it.Tags, ", "),
+		it.Notes,
+		it.AddedAt.UTC().Format(time.RFC3339),
+	}); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExporter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// letterboxdExporter writes Letterboxd's import format. Letterboxd only lists
+// films, so TV shows are skipped.
+type letterboxdExporter struct {
+	w *csv.Writer
+}
+
+func (e *letterboxdExporter) begin() error {
+	return e.w.Write([]string{"tmdbID", "imdbID", "Title", "Year", "Tags"})
+}
+
+func (e *letterboxdExporter) write(it WatchlistItemResponse) error {
+	if it.MediaType == "tv" {
+		return nil
+	}
+	if err := e.w.Write([]string{
+		strconv.FormatUint(uint64(it.MovieID), 10),
+		it.IMDbID,
+		it.Title,
+		optionalInt(it.Year),
+		strings.Join(it.Tags, ", "),
+	}); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *letterboxdExporter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonExporter writes {"exported_at": ..., "items": [...]} one item at a time
+type jsonExporter struct {
+	c     *gin.Context
+	count int
+}
+
+func (e *jsonExporter) begin() error {
+	_, err := fmt.Fprintf(e.c.Writer, `{"exported_at":%q,"items":[`, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+func (e *jsonExporter) write(it WatchlistItemResponse) error {
+	data, err := json.Marshal(it)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := e.c.Writer.Write([]byte(",")); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.c.Writer.Write(data)
+	return err
+}
+
+func (e *jsonExporter) end() error {
+	_, err := fmt.Fprintf(e.c.Writer, `],"count":%d}`, e.count)
+	return err
+}
+
+func optionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,49 @@
+package deliveryhttp
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/HMZ-H/moviemate/internal/usecase"
+)
+
+// The Letterboxd export must import back in as the same titles
+func TestLetterboxdExportImportsBack(t *testing.T) {
+	items := []WatchlistItemResponse{
+		{MovieID: 27205, MediaType: "movie", IMDbID: "tt1375666", Title: "Inception", Year: 2010, Tags: []string{"heist", "dreams"}},
+		{MovieID: 157336, MediaType: "movie", Title: "Interstellar, Part One", Tags: []string{}},
+		{MovieID: 1396, MediaType: "tv", IMDbID: "tt0903747", Title: "Breaking Bad", Year: 2008},
+	}
+	var buf bytes.Buffer
+	exp := &letterboxdExporter{w: csv.NewWriter(&buf)}
+	if err := exp.begin(); err != nil {
+		t.Fatal(err)
+	}
+	for _, it := range items {
+		if err := exp.write(it); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := exp.end(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rows carrying a TMDB ID never reach the matcher
+	report, err := usecase.NewImporter(nil, nil, nil).Import(1, "moviemate-letterboxd.csv", buf.Bytes(), "", true)
+	if err != nil {
+		t.Fatalf("import: %v\n%s", err, buf.String())
+	}
+	if report.Source != usecase.ImportLetterboxd {
+		t.Errorf("source = %q, want %q", report.Source, usecase.ImportLetterboxd)
+	}
+	if len(report.Matched) != 2 || len(report.Ambiguous) != 0 || len(report.Failed) != 0 {
+		t.Fatalf("report = %+v, want the two films matched", report)
+	}
+	for i, res := range report.Matched {
+		want := items[i]
+		if res.TMDBID != want.MovieID || res.MatchedBy != "tmdb_id" || res.Title != want.Title || res.Year != want.Year {
+			t.Errorf("row %d = %+v, want %s (%d) matched by tmdb_id", i, res, want.Title, want.MovieID)
+		}
+	}
+}
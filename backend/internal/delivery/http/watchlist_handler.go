@@ -1,16 +1,47 @@
 package deliveryhttp
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	stdhttp "net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/HMZ-H/moviemate/internal/domain"
 	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/HMZ-H/moviemate/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
 
 type WatchlistRequest struct {
-	MovieID int `json:"movie_id" binding:"required"`
+	MovieID  int    `json:"movie_id" binding:"required"`
+	Priority string `json:"priority" binding:"omitempty,oneof=must-watch someday"`
+}
+
+type WatchlistItemUpdateRequest struct {
+	Priority *string  `json:"priority" binding:"omitempty,oneof=must-watch someday"`
+	Notes    *string  `json:"notes" binding:"omitempty,max=2000"`
+	Tags     []string `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+}
+
+// WatchlistBulkRequest adds or removes up to 500 titles at once
+type WatchlistBulkRequest struct {
+	Action   string `json:"action" binding:"required,oneof=add remove"`
+	MovieIDs []uint `json:"movie_ids" binding:"required,min=1,max=500,dive,min=1"`
+}
+
+// WatchlistUndoRequest restores recently removed titles
+type WatchlistUndoRequest struct {
+	MovieIDs []uint `json:"movie_ids" binding:"required,min=1,max=500,dive,min=1"`
+}
+
+// WatchlistOrderRequest moves one item; AfterID 0 moves it to the top
+type WatchlistOrderRequest struct {
+	MovieID int `json:"movie_id" binding:"required,min=1"`
+	AfterID int `json:"after_id" binding:"min=0"`
 }
 
 type WatchlistResponse struct {
@@ -18,12 +49,55 @@ type WatchlistResponse struct {
 	Success bool   `json:"success"`
 }
 
+// WatchlistItemResponse is a watchlist entry hydrated from the metadata cache
+type WatchlistItemResponse struct {
+	MovieID    uint      `json:"movie_id"`
+	MediaType  string    `json:"media_type,omitempty"`
+	IMDbID     string    `json:"imdb_id,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	PosterPath string    `json:"poster_path,omitempty"`
+	Year       int       `json:"year,omitempty"`
+	Runtime    int       `json:"runtime,omitempty"`
+	Rating     float64   `json:"rating,omitempty"`
+	Genres     []string  `json:"genres"`
+	Priority   string    `json:"priority"`
+	Notes      string    `json:"notes,omitempty"`
+	Tags       []string  `json:"tags"`
+	Rank       float64   `json:"rank"`
+	AddedBy    uint      `json:"added_by,omitempty"`
+	AddedAt    time.Time `json:"added_at"`
+	// Providers lists where the title can be watched in the response's region
+	Providers []domain.WatchOffer `json:"providers,omitempty"`
+}
+
+// defaultRegion is used for watch providers when the user has no region
+const defaultRegion = "US"
+
+// Page size bounds for GET /api/watchlist
+const (
+	defaultWatchlistLimit = 50
+	maxWatchlistLimit     = 200
+)
+
 type WatchlistHandler struct {
-	watchlistRepo repository.WatchlistRepo
+	watchlistRepo    repository.WatchlistRepo
+	lists            repository.ListRepo
+	availabilityRepo repository.AvailabilityRepo
+	metadata         *usecase.MetadataCache
+	availability     *usecase.AvailabilityCache
+	undoWindow       time.Duration // how long a removal can be undone
 }
 
-func NewWatchlistHandler(watchlistRepo repository.WatchlistRepo) *WatchlistHandler {
-	return &WatchlistHandler{watchlistRepo: watchlistRepo}
+func NewWatchlistHandler(watchlistRepo repository.WatchlistRepo, lists repository.ListRepo, availabilityRepo repository.AvailabilityRepo,
+	metadata *usecase.MetadataCache, availability *usecase.AvailabilityCache, undoWindow time.Duration) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlistRepo:    watchlistRepo,
+		lists:            lists,
+		availabilityRepo: availabilityRepo,
+		metadata:         metadata,
+		availability:     availability,
+		undoWindow:       undoWindow,
+	}
 }
 
 func (h *WatchlistHandler) AddToWatchlist(c *gin.Context) {
@@ -44,13 +118,22 @@ func (h *WatchlistHandler) AddToWatchlist(c *gin.Context) {
 	}
 	userID := userIDInterface.(uint)
 
+	list, ok := h.resolveList(c, userID, domain.RoleEditor)
+	if !ok {
+		return
+	}
+
 	// Create watchlist item
 	item := &domain.WatchlistItem{
-		UserID:  userID,
-		MovieID: uint(req.MovieID),
+		UserID:    list.OwnerID,
+		ListID:    list.ListID,
+		MovieID:   uint(req.MovieID),
+		AddedByID: userID,
+		Priority:  req.Priority,
 	}
 
-	if err := h.watchlistRepo.AddWatchlist(item); err != nil {
+	created, err := h.watchlistRepo.AddWatchlist(item)
+	if err != nil {
 		log.Printf("Error adding to watchlist: %v", err)
 		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
 			Message: "Failed to add to watchlist",
@@ -58,8 +141,15 @@ func (h *WatchlistHandler) AddToWatchlist(c *gin.Context) {
 		})
 		return
 	}
+	if !created {
+		c.JSON(stdhttp.StatusOK, WatchlistResponse{
+			Message: "Already in watchlist",
+			Success: true,
+		})
+		return
+	}
 
-	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+	c.JSON(stdhttp.StatusCreated, WatchlistResponse{
 		Message: "Added to watchlist successfully",
 		Success: true,
 	})
@@ -80,7 +170,13 @@ func (h *WatchlistHandler) RemoveFromWatchlist(c *gin.Context) {
 	}
 	userID := userIDInterface.(uint)
 
-	if err := h.watchlistRepo.RemoveWatchlist(userID, uint(req.MovieID)); err != nil {
+	list, ok := h.resolveList(c, userID, domain.RoleEditor)
+	if !ok {
+		return
+	}
+
+	removed, err := h.watchlistRepo.RemoveWatchlist(list, uint(req.MovieID))
+	if err != nil {
 		log.Printf("Error removing from watchlist: %v", err)
 		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
 			Message: "Failed to remove from watchlist",
@@ -88,6 +184,13 @@ func (h *WatchlistHandler) RemoveFromWatchlist(c *gin.Context) {
 		})
 		return
 	}
+	if !removed {
+		c.JSON(stdhttp.StatusNotFound, WatchlistResponse{
+			Message: "Item not in watchlist",
+			Success: false,
+		})
+		return
+	}
 
 	c.JSON(stdhttp.StatusOK, WatchlistResponse{
 		Message: "Removed from watchlist successfully",
@@ -95,6 +198,94 @@ func (h *WatchlistHandler) RemoveFromWatchlist(c *gin.Context) {
 	})
 }
 
+// BulkWatchlist adds or removes many titles in one transaction (POST /api/watchlist/bulk)
+func (h *WatchlistHandler) BulkWatchlist(c *gin.Context) {
+	var req WatchlistBulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.resolveList(c, userID, domain.RoleEditor)
+	if !ok {
+		return
+	}
+
+	results, err := h.watchlistRepo.BulkWatchlist(list, userID, req.Action, req.MovieIDs)
+	if err != nil {
+		log.Printf("Error in bulk watchlist %s: %v", req.Action, err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to update watchlist",
+			Success: false,
+		})
+		return
+	}
+
+	summary := map[string]int{}
+	for _, r := range results {
+		summary[r.Status]++
+	}
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"results": results,
+		"summary": summary,
+		"success": true,
+	})
+}
+
+// UndoRemove restores titles removed within the undo window, in their old
+// position with their notes and tags (POST /api/watchlist/undo)
+func (h *WatchlistHandler) UndoRemove(c *gin.Context) {
+	var req WatchlistUndoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.resolveList(c, userID, domain.RoleEditor)
+	if !ok {
+		return
+	}
+
+	results, err := h.watchlistRepo.RestoreWatchlist(list, req.MovieIDs, time.Now().Add(-h.undoWindow))
+	if err != nil {
+		log.Printf("Error restoring watchlist items: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to restore watchlist items",
+			Success: false,
+		})
+		return
+	}
+
+	summary := map[string]int{}
+	for _, r := range results {
+		summary[r.Status]++
+	}
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"results": results,
+		"summary": summary,
+		"success": true,
+	})
+}
+
+// GetWatchlist returns one page of the user's watchlist. Bare TMDB IDs are
+// returned unless expand=details is set; expand=providers also adds where each
+// title can be watched in the user's region.
 func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
 	// Get user ID from authenticated context
 	userIDInterface, exists := c.Get("user_id")
@@ -104,16 +295,480 @@ func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
 	}
 	userID := userIDInterface.(uint)
 
-	// Return TMDB IDs so frontend can fetch details from TMDB
-	ids, err := h.watchlistRepo.ListWatchlistIDsByUser(userID)
+	list, ok := h.resolveList(c, userID, domain.RoleViewer)
+	if !ok {
+		return
+	}
+
+	query, err := parseWatchlistQuery(c, list)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.applyUserServices(c, userID, &query) {
+		return
+	}
+	expand := parseExpand(c)
+
+	// Any write bumps the list version, so an unchanged version means an unchanged
+	// response. Availability isn't versioned, so responses using it expire daily.
+	version, err := h.watchlistRepo.GetWatchlistVersion(list)
+	if err != nil {
+		log.Printf("Error fetching watchlist version: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watchlist"})
+		return
+	}
+	etagKey := c.Request.URL.RawQuery
+	if query.Filter.NeedsAvailability() || expand["providers"] {
+		etagKey += fmt.Sprintf("&resolved=%s%v@%s", query.Filter.Region, query.Filter.ProviderIDs, time.Now().UTC().Format("2006-01-02"))
+	}
+	if expand["details"] || expand["providers"] || usesMetadata(query) {
+		stamp, err := h.metadataStamp(list)
+		if err != nil {
+			log.Printf("Error fetching watchlist metadata stamp: %v", err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watchlist"})
+			return
+		}
+		etagKey += stamp
+	}
+	etag := watchlistETag(list, version, etagKey)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(stdhttp.StatusNotModified)
+		return
+	}
+
+	// Filters and sorts over metadata run in SQL, so make sure it's cached first
+	if usesMetadata(query) {
+		if err := h.warmMetadata(list, query.Filter); err != nil {
+			log.Printf("Error warming watchlist metadata: %v", err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watchlist"})
+			return
+		}
+	}
+
+	page, err := h.watchlistRepo.ListWatchlistPage(query)
 	if err != nil {
-		log.Printf("Error fetching watchlist IDs: %v", err)
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		log.Printf("Error fetching watchlist: %v", err)
 		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watchlist"})
 		return
 	}
 
+	if expand["details"] || expand["providers"] {
+		region := ""
+		if expand["providers"] {
+			region = query.Filter.Region
+		}
+		h.getWatchlistDetails(c, page, version, region)
+		return
+	}
+
+	// Return TMDB IDs so frontend can fetch details from TMDB
+	ids := make([]uint, len(page.Items))
+	for i, item := range page.Items {
+		ids[i] = item.MovieID
+	}
+
 	c.JSON(stdhttp.StatusOK, gin.H{
-		"items": ids,
-		"count": len(ids),
+		"items":       ids,
+		"count":       len(ids),
+		"total":       page.Total,
+		"next_cursor": page.NextCursor,
+		"version":     version,
 	})
 }
+
+// GetWatchlistTags lists the user's tags with item counts
+func (h *WatchlistHandler) GetWatchlistTags(c *gin.Context) {
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.resolveList(c, userID, domain.RoleViewer)
+	if !ok {
+		return
+	}
+
+	tags, err := h.watchlistRepo.ListWatchlistTags(list)
+	if err != nil {
+		log.Printf("Error fetching watchlist tags: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"tags":  tags,
+		"count": len(tags),
+	})
+}
+
+// UpdateWatchlistItem edits a single item (PATCH /api/watchlist/:movie_id)
+func (h *WatchlistHandler) UpdateWatchlistItem(c *gin.Context) {
+	movieID, err := strconv.ParseUint(c.Param("movie_id"), 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid movie ID"})
+		return
+	}
+	var req WatchlistItemUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.resolveList(c, userID, domain.RoleEditor)
+	if !ok {
+		return
+	}
+
+	patch := domain.WatchlistItemPatch{Priority: req.Priority, Notes: req.Notes}
+	if req.Tags != nil {
+		tags := normalizeTags(req.Tags)
+		patch.Tags = &tags
+	}
+	if err := h.watchlistRepo.UpdateWatchlistItem(list, uint(movieID), patch); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Item not in watchlist"})
+			return
+		}
+		log.Printf("Error updating watchlist item: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to update watchlist item",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+		Message: "Watchlist item updated",
+		Success: true,
+	})
+}
+
+// ReorderWatchlist moves an item within the list (PATCH /api/watchlist/order)
+func (h *WatchlistHandler) ReorderWatchlist(c *gin.Context) {
+	var req WatchlistOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+	if req.AfterID == req.MovieID {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Cannot move an item after itself"})
+		return
+	}
+
+	// Get user ID from authenticated context
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(stdhttp.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	list, ok := h.resolveList(c, userID, domain.RoleEditor)
+	if !ok {
+		return
+	}
+
+	if err := h.watchlistRepo.MoveWatchlistItem(list, uint(req.MovieID), uint(req.AfterID)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(stdhttp.StatusNotFound, gin.H{"error": "Item not in watchlist"})
+			return
+		}
+		log.Printf("Error reordering watchlist: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, WatchlistResponse{
+			Message: "Failed to reorder watchlist",
+			Success: false,
+		})
+		return
+	}
+
+	c.JSON(stdhttp.StatusOK, WatchlistResponse{
+		Message: "Watchlist reordered",
+		Success: true,
+	})
+}
+
+// getWatchlistDetails serves GET /api/watchlist?expand=details. Watch offers
+// are included when region is set.
+func (h *WatchlistHandler) getWatchlistDetails(c *gin.Context, page *domain.WatchlistPage, version int64, region string) {
+	ids := make([]uint, len(page.Items))
+	for i, item := range page.Items {
+		ids[i] = item.MovieID
+	}
+	meta, err := h.metadata.Lookup(ids)
+	if err != nil {
+		log.Printf("Error loading watchlist metadata: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watchlist"})
+		return
+	}
+
+	var offers map[uint][]domain.WatchOffer
+	if region != "" {
+		if offers, err = h.availability.Lookup(meta, region); err != nil {
+			log.Printf("Error loading watch providers: %v", err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watchlist"})
+			return
+		}
+	}
+
+	resp := make([]WatchlistItemResponse, 0, len(page.Items))
+	for _, item := range page.Items {
+		r := newWatchlistItemResponse(item, meta[item.MovieID])
+		r.Providers = offers[item.MovieID]
+		resp = append(resp, r)
+	}
+
+	body := gin.H{
+		"items":       resp,
+		"count":       len(resp),
+		"total":       page.Total,
+		"next_cursor": page.NextCursor,
+		"version":     version,
+	}
+	if region != "" {
+		body["region"] = region
+	}
+	c.JSON(stdhttp.StatusOK, body)
+}
+
+// resolveList maps the list_id query parameter to a list the user holds at
+// least the given role on. An absent or zero list_id is their personal
+// watchlist. On failure the response has been written and ok is false.
+func (h *WatchlistHandler) resolveList(c *gin.Context, userID uint, need string) (domain.ListRef, bool) {
+	raw := c.Query("list_id")
+	if raw == "" || raw == "0" {
+		return domain.ListRef{OwnerID: userID}, true
+	}
+	listID, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid list ID"})
+		return domain.ListRef{}, false
+	}
+
+	list, _, ok := checkListAccess(c, h.lists, uint(listID), userID, need)
+	if !ok {
+		return domain.ListRef{}, false
+	}
+	return domain.ListRef{OwnerID: list.OwnerID, ListID: list.ID}, true
+}
+
+// usesMetadata reports whether the query filters or sorts on cached metadata
+func usesMetadata(q domain.WatchlistQuery) bool {
+	return q.Filter.NeedsMetadata() || (q.Sort != domain.SortRank && q.Sort != domain.SortAdded)
+}
+
+// warmMetadata makes sure every item on the list has cached metadata, and
+// cached watch offers too when the filter reads them. Only titles missing
+// them, or with stale ones, go to the providers.
+func (h *WatchlistHandler) warmMetadata(list domain.ListRef, filter domain.WatchlistFilter) error {
+	ids, err := h.watchlistRepo.ListWatchlistIDsNeedingMetadata(list, h.metadata.StaleBefore())
+	if err != nil {
+		return err
+	}
+	if !filter.NeedsAvailability() {
+		if len(ids) == 0 {
+			return nil
+		}
+		_, err = h.metadata.Lookup(ids)
+		return err
+	}
+	offerIDs, err := h.watchlistRepo.ListWatchlistIDsNeedingOffers(list, h.availability.StaleBefore())
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 && len(offerIDs) == 0 {
+		return nil
+	}
+	// Offers are fetched by media type, so their titles need metadata as well
+	ids = append(ids, offerIDs...)
+	slices.Sort(ids)
+	meta, err := h.metadata.Lookup(slices.Compact(ids))
+	if err != nil || len(offerIDs) == 0 {
+		return err
+	}
+	needOffers := make(map[uint]domain.MovieMetadata, len(offerIDs))
+	for _, id := range offerIDs {
+		if m, ok := meta[id]; ok {
+			needOffers[id] = m
+		}
+	}
+	_, err = h.availability.Lookup(needOffers, filter.Region)
+	return err
+}
+
+// applyUserServices defaults the query's region to the user's and, for
+// on_my_services=true, restricts it to the user's saved services. On failure
+// the response has been written and false is returned.
+func (h *WatchlistHandler) applyUserServices(c *gin.Context, userID uint, q *domain.WatchlistQuery) bool {
+	if q.Filter.Region == "" {
+		region, err := h.availabilityRepo.GetUserRegion(userID)
+		if err != nil {
+			log.Printf("Error fetching user region: %v", err)
+			c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watchlist"})
+			return false
+		}
+		q.Filter.Region = region
+		if region == "" {
+			q.Filter.Region = defaultRegion
+		}
+	}
+	if c.Query("on_my_services") != "true" {
+		return true
+	}
+	services, err := h.availabilityRepo.GetUserServices(userID)
+	if err != nil {
+		log.Printf("Error fetching user services: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch watchlist"})
+		return false
+	}
+	if len(services) == 0 {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "No streaming services saved; set them with PUT /api/profile/services"})
+		return false
+	}
+	q.Filter.ProviderIDs = append(q.Filter.ProviderIDs, services...)
+	return true
+}
+
+// parseExpand reads the comma-separated expand parameter
+func parseExpand(c *gin.Context) map[string]bool {
+	expand := map[string]bool{}
+	for _, v := range strings.Split(c.Query("expand"), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			expand[v] = true
+		}
+	}
+	return expand
+}
+
+// newWatchlistItemResponse merges a watchlist row with its cached metadata.
+// Items missing from the cache are returned with just their ID and date.
+func newWatchlistItemResponse(item domain.WatchlistItem, m domain.MovieMetadata) WatchlistItemResponse {
+	genres := []string{}
+	if m.Genres != "" {
+		genres = strings.Split(m.Genres, ",")
+	}
+	tags := make([]string, len(item.Tags))
+	for i, t := range item.Tags {
+		tags[i] = t.Name
+	}
+	return WatchlistItemResponse{
+		MovieID:    item.MovieID,
+		MediaType:  m.MediaType,
+		IMDbID:     m.IMDbID,
+		Title:      m.Title,
+		PosterPath: m.PosterPath,
+		Year:       m.ReleaseYear,
+		Runtime:    m.Runtime,
+		Rating:     m.Rating,
+		Genres:     genres,
+		Priority:   item.Priority,
+		Notes:      item.Notes,
+		Tags:       tags,
+		Rank:       item.Rank,
+		AddedBy:    item.AddedByID,
+		AddedAt:    item.AddedAt,
+	}
+}
+
+// parseWatchlistQuery reads paging, sorting and filter parameters:
+// limit, cursor, sort, order, tag, genre, media_type, year_from, year_to,
+// runtime_min, runtime_max, provider and region.
+func parseWatchlistQuery(c *gin.Context, list domain.ListRef) (domain.WatchlistQuery, error) {
+	q := domain.WatchlistQuery{
+		List:   list,
+		Sort:   c.DefaultQuery("sort", domain.SortRank),
+		Cursor: c.Query("cursor"),
+		Limit:  defaultWatchlistLimit,
+	}
+	if !repository.ValidWatchlistSort(q.Sort) {
+		return q, errors.New("sort must be one of rank, added, title, year, rating")
+	}
+	switch c.Query("order") {
+	case "":
+		q.Desc = repository.DefaultWatchlistSortDesc(q.Sort)
+	case "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return q, errors.New("order must be asc or desc")
+	}
+
+	ints := []struct {
+		name string
+		dst  *int
+	}{
+		{"limit", &q.Limit},
+		{"year_from", &q.Filter.YearFrom},
+		{"year_to", &q.Filter.YearTo},
+		{"runtime_min", &q.Filter.RuntimeMin},
+		{"runtime_max", &q.Filter.RuntimeMax},
+	}
+	for _, p := range ints {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return q, fmt.Errorf("%s must be a non-negative integer", p.name)
+		}
+		*p.dst = v
+	}
+	if q.Limit <= 0 || q.Limit > maxWatchlistLimit {
+		return q, fmt.Errorf("limit must be between 1 and %d", maxWatchlistLimit)
+	}
+
+	q.Filter.Tags = normalizeTags(c.QueryArray("tag"))
+	q.Filter.Genre = strings.TrimSpace(c.Query("genre"))
+	q.Filter.MediaType = c.Query("media_type")
+	if q.Filter.MediaType != "" && q.Filter.MediaType != "movie" && q.Filter.MediaType != "tv" {
+		return q, errors.New("media_type must be movie or tv")
+	}
+
+	for _, raw := range c.QueryArray("provider") {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return q, errors.New("provider must be a TMDB watch provider ID")
+		}
+		q.Filter.ProviderIDs = append(q.Filter.ProviderIDs, id)
+	}
+	if region := c.Query("region"); region != "" {
+		if len(region) != 2 {
+			return q, errors.New("region must be a two-letter country code")
+		}
+		q.Filter.Region = strings.ToUpper(region)
+	}
+	return q, nil
+}
+
+// normalizeTags lowercases tags, strips a leading '#' and drops blanks and duplicates
+func normalizeTags(raw []string) []string {
+	seen := make(map[string]bool, len(raw))
+	tags := make([]string, 0, len(raw))
+	for _, t := range raw {
+		t = strings.ToLower(strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(t), "#")))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	return tags
+}
@@ -0,0 +1,31 @@
+package deliveryhttp_test
+
+import (
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	deliveryhttp "github.com/HMZ-H/moviemate/internal/delivery/http"
+	"github.com/gin-gonic/gin"
+)
+
+func TestReorderWatchlistRejectsBadIDs(t *testing.T) {
+	h := deliveryhttp.NewWatchlistHandler(nil, nil, nil, nil, nil, 0)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PATCH("/api/watchlist/order", h.ReorderWatchlist)
+
+	for _, body := range []string{
+		`{}`,
+		`{"movie_id": -1}`,
+		`{"movie_id": 27205, "after_id": -1}`,
+		`{"movie_id": 27205, "after_id": 27205}`,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(stdhttp.MethodPatch, "/api/watchlist/order", strings.NewReader(body)))
+		if w.Code != stdhttp.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, w.Code)
+		}
+	}
+}
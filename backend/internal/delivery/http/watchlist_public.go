@@ -0,0 +1,121 @@
+package deliveryhttp
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	stdhttp "net/http"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// publicListCacheControl lets browsers and CDNs reuse a public list briefly.
+// A revoked or rotated link may keep being served from caches for this long.
+const publicListCacheControl = "public, max-age=60"
+
+// GetPublicList serves a public list by slug without authentication
+// (GET /api/public/lists/:slug). It takes the same paging, sort and filter
+// parameters as GET /api/watchlist and always returns hydrated items. Notes
+// and tags are private unless the owner chose to share them.
+// Metadata and watch offers are read from the cache only, so anonymous
+// callers can't make the server fetch from TMDB; titles the cache doesn't
+// have yet are sorted and filtered as if their details were unknown.
+func (h *WatchlistHandler) GetPublicList(c *gin.Context) {
+	list, err := h.lists.GetPublicList(c.Param("slug"))
+	if err != nil {
+		log.Printf("Error loading public list: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch list"})
+		return
+	}
+	if list == nil {
+		c.Header("Cache-Control", "no-store")
+		c.JSON(stdhttp.StatusNotFound, gin.H{"error": "List not found"})
+		return
+	}
+	ref := domain.ListRef{OwnerID: list.OwnerID, ListID: list.ID}
+
+	query, err := parseWatchlistQuery(c, ref)
+	if err != nil {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.Filter.Region == "" {
+		query.Filter.Region = defaultRegion
+	}
+	// Filtering by a private tag would reveal which items carry it
+	if len(query.Filter.Tags) > 0 && !list.ShareNotes {
+		c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "This list's tags aren't shared"})
+		return
+	}
+
+	version, err := h.watchlistRepo.GetWatchlistVersion(ref)
+	if err != nil {
+		log.Printf("Error fetching watchlist version: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch list"})
+		return
+	}
+	// The list name is in the body too, so renames must change the tag, and
+	// availability isn't versioned, so provider filters expire daily
+	etagKey := fmt.Sprintf("%s&updated=%d", c.Request.URL.RawQuery, list.UpdatedAt.UnixNano())
+	if query.Filter.NeedsAvailability() {
+		etagKey += "@" + time.Now().UTC().Format("2006-01-02")
+	}
+	stamp, err := h.metadataStamp(ref)
+	if err != nil {
+		log.Printf("Error fetching watchlist metadata stamp: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch list"})
+		return
+	}
+	etagKey += stamp
+	etag := watchlistETag(ref, version, etagKey)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", publicListCacheControl)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(stdhttp.StatusNotModified)
+		return
+	}
+	page, err := h.watchlistRepo.ListWatchlistPage(query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			c.JSON(stdhttp.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		log.Printf("Error fetching public list %d: %v", list.ID, err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch list"})
+		return
+	}
+	ids := make([]uint, len(page.Items))
+	for i, item := range page.Items {
+		ids[i] = item.MovieID
+	}
+	meta, err := h.metadata.Cached(ids)
+	if err != nil {
+		log.Printf("Error loading watchlist metadata: %v", err)
+		c.JSON(stdhttp.StatusInternalServerError, gin.H{"error": "Failed to fetch list"})
+		return
+	}
+
+	items := make([]WatchlistItemResponse, 0, len(page.Items))
+	for _, item := range page.Items {
+		resp := newWatchlistItemResponse(item, meta[item.MovieID])
+		resp.AddedBy = 0 // don't expose member IDs
+		if !list.ShareNotes {
+			resp.Notes = ""
+			resp.Tags = []string{}
+		}
+		items = append(items, resp)
+	}
+
+	c.JSON(stdhttp.StatusOK, gin.H{
+		"list": gin.H{
+			"name": list.Name,
+		},
+		"items":       items,
+		"count":       len(items),
+		"total":       page.Total,
+		"next_cursor": page.NextCursor,
+	})
+}
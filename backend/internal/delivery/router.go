@@ -1,13 +1,19 @@
 package delivery
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 
 	deliveryhttp "github.com/HMZ-H/moviemate/internal/delivery/http"
+	"github.com/HMZ-H/moviemate/internal/domain"
 	"github.com/HMZ-H/moviemate/internal/infra"
 	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/HMZ-H/moviemate/internal/usecase"
 	tollbooth "github.com/didip/tollbooth/v7"
 	tollbooth_gin "github.com/didip/tollbooth_gin"
 	"github.com/gin-contrib/cors"
@@ -22,12 +28,13 @@ func SetupRouter() *gin.Engine {
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{
 		"http://localhost:3000",
-		"http://localhost:5173", 
+		"http://localhost:5173",
 		"http://localhost:5174",
 		"https://moviemate-frontend-txyl.onrender.com", // Your actual deployed frontend URL
 	}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "If-None-Match"}
+	config.ExposeHeaders = []string{"ETag"} // so clients can sync the watchlist with If-None-Match
 	config.AllowCredentials = true
 	r.Use(cors.New(config))
 
@@ -38,8 +45,124 @@ func SetupRouter() *gin.Engine {
 	}
 	userRepo := repository.NewGormRepo(db)
 	watchlistRepo := repository.NewGormRepo(db)
+
+	// TMDB is optional; without it only cached metadata is served and imports are off
+	var metadataProvider domain.MetadataProvider
+	var tvProvider domain.TVProvider
+	var importer *usecase.Importer
+	var tmdb *infra.TMDBMetadataService
+	tmdbClient, err := infra.NewTMDBClientFromEnv()
+	if err == nil {
+		tmdb = infra.NewTMDBMetadataService(tmdbClient)
+		metadataProvider = tmdb
+		tvProvider = tmdb
+		importer = usecase.NewImporter(watchlistRepo, watchlistRepo, tmdb)
+	} else {
+		log.Printf("TMDB metadata disabled: %v", err)
+	}
+	metadataCache := usecase.NewMetadataCache(watchlistRepo, metadataProvider)
+
+	// Watch providers come from TMDB, or from a fixture file for local development
+	var watchProviders domain.WatchProviderService
+	if tmdb != nil {
+		watchProviders = tmdb
+	} else if fixture, err := infra.NewFixtureWatchProviderServiceFromEnv(); err == nil {
+		watchProviders = fixture
+	} else {
+		log.Printf("Watch providers disabled: %v", err)
+	}
+	availabilityCache := usecase.NewAvailabilityCache(watchlistRepo, watchProviders)
+
+	// The catalog proxy shares the TMDB client, or replays a fixture file for local development
+	var catalog domain.CatalogProvider
+	if tmdbClient != nil {
+		catalog = infra.NewTMDBCatalogService(tmdbClient)
+	} else if fixture, err := infra.NewFixtureCatalogServiceFromEnv(); err == nil {
+		catalog = fixture
+	} else {
+		log.Printf("Catalog proxy disabled: %v", err)
+	}
+
+	// Catalog responses are cached in memory, and also in Postgres when
+	// CATALOG_SHARED_CACHE=postgres so every instance can reuse them
+	var catalogCache *usecase.CachedCatalog
+	if catalog != nil {
+		var shared domain.CacheStore
+		if os.Getenv("CATALOG_SHARED_CACHE") == "postgres" {
+			shared = watchlistRepo
+			go usecase.NewCachePurger(watchlistRepo).Start(context.Background(), envDuration("CACHE_PURGE_INTERVAL", time.Hour))
+		}
+		catalogCache = usecase.NewCachedCatalog(catalog, shared, envInt("CATALOG_CACHE_SIZE", 2000))
+		catalog = catalogCache
+	}
+
+	// Genres are seeded at migration and refreshed from TMDB's lists
+	genres := usecase.NewGenreService(watchlistRepo, catalog)
+	if catalog != nil {
+		go genres.Start(context.Background(), envDuration("GENRE_REFRESH_INTERVAL", 24*time.Hour))
+	}
+
+	// Release reminders need TMDB release dates; email falls back to the log
+	if tmdb != nil {
+		var sender domain.NotificationSender = infra.NewLogNotificationSender()
+		if smtpSender, err := infra.NewSMTPNotificationSenderFromEnv(); err == nil {
+			sender = smtpSender
+		} else {
+			log.Printf("Reminder emails disabled: %v", err)
+		}
+		reminders := usecase.NewReleaseReminders(watchlistRepo, metadataCache, tmdb, sender, envInt("RELEASE_REMINDER_DAYS", 7))
+		go reminders.Start(context.Background(), envDuration("RELEASE_REMINDER_INTERVAL", 6*time.Hour))
+	}
+
+	// The catalog sync fills the Movie table from TMDB's daily ID export
+	if tmdbClient != nil {
+		if exportPath, opts := CatalogSyncOptionsFromEnv(); exportPath != "" {
+			catalogSync := usecase.NewCatalogSync(watchlistRepo, infra.NewTMDBCatalogService(tmdbClient), exportPath, opts)
+			go catalogSync.Start(context.Background(), envDuration("CATALOG_SYNC_INTERVAL", 24*time.Hour))
+		}
+	}
+
+	// Removed items can be restored for the undo window and are purged once
+	// they have been gone for the retention period
+	undoWindow := envDuration("WATCHLIST_UNDO_WINDOW", 2*time.Minute)
+	retention := envDuration("WATCHLIST_TOMBSTONE_RETENTION", 24*time.Hour)
+	if retention < undoWindow {
+		retention = undoWindow
+	}
+	purger := usecase.NewTombstonePurger(watchlistRepo, retention)
+	go purger.Start(context.Background(), envDuration("WATCHLIST_PURGE_INTERVAL", time.Hour))
+
+	// Image proxy: TMDB images scaled to preset sizes, cached on local disk.
+	// IMAGE_CACHE_MAX_MB=0 turns the cache off.
+	var imageCache domain.BlobCache
+	if maxMB := envInt("IMAGE_CACHE_MAX_MB", 1024); maxMB > 0 {
+		dir := os.Getenv("IMAGE_CACHE_DIR")
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "moviemate-images")
+		}
+		if diskCache, err := infra.NewDiskCache(dir, int64(maxMB)<<20); err == nil {
+			imageCache = diskCache
+		} else {
+			log.Printf("Image cache disabled: %v", err)
+		}
+	}
+	imageProxy := usecase.NewImageProxy(infra.NewImageOriginFromEnv(), imageCache)
+
 	authHandler := deliveryhttp.NewAuthHandler(userRepo)
-	watchlistHandler := deliveryhttp.NewWatchlistHandler(watchlistRepo)
+	watchlistHandler := deliveryhttp.NewWatchlistHandler(watchlistRepo, watchlistRepo, watchlistRepo, metadataCache, availabilityCache, undoWindow)
+	listHandler := deliveryhttp.NewListHandler(watchlistRepo)
+	importHandler := deliveryhttp.NewImportHandler(importer)
+	notificationHandler := deliveryhttp.NewNotificationHandler(watchlistRepo)
+	availabilityHandler := deliveryhttp.NewAvailabilityHandler(watchlistRepo, metadataCache, availabilityCache)
+	tvHandler := deliveryhttp.NewTVHandler(usecase.NewTVProgress(watchlistRepo, tvProvider))
+	groupHandler := deliveryhttp.NewGroupHandler(watchlistRepo, userRepo, usecase.NewGroupPicker(watchlistRepo, watchlistRepo, metadataCache))
+	catalogHandler := deliveryhttp.NewCatalogHandler(catalog, catalogCache)
+	genreHandler := deliveryhttp.NewGenreHandler(genres)
+	movies := usecase.NewMovieUsecase(userRepo, watchlistRepo, watchlistRepo, watchlistRepo, watchlistRepo)
+	searchHandler := deliveryhttp.NewSearchHandler(movies, envDuration("SEARCH_SUGGEST_BUDGET", 150*time.Millisecond))
+	adminMovieHandler := deliveryhttp.NewAdminMovieHandler(movies)
+	peopleHandler := deliveryhttp.NewPeopleHandler(watchlistRepo)
+	imageHandler := deliveryhttp.NewImageHandler(imageProxy)
 
 	// Authentication routes (public)
 	auth := r.Group("/api/auth")
@@ -48,14 +171,88 @@ func SetupRouter() *gin.Engine {
 		auth.POST("/login", authHandler.Login)
 	}
 
+	// Public list links (no authentication)
+	public := r.Group("/api/public")
+	{
+		public.GET("/lists/:slug", watchlistHandler.GetPublicList)
+	}
+
+	// TMDB catalog proxy (no authentication)
+	catalogRoutes := r.Group("/api/catalog")
+	{
+		catalogRoutes.GET("/trending", catalogHandler.GetTrending)
+		catalogRoutes.GET("/discover", catalogHandler.GetDiscover)
+		catalogRoutes.GET("/search", catalogHandler.Search)
+		catalogRoutes.GET("/:media_type/:id", catalogHandler.GetTitle)
+		catalogRoutes.GET("/:media_type/:id/videos", catalogHandler.GetVideos)
+		catalogRoutes.GET("/:media_type/:id/images", catalogHandler.GetImages)
+	}
+
+	// Genre list, movie lookup and search, people and images (no authentication)
+	r.GET("/api/genres", genreHandler.ListGenres)
+	r.GET("/api/movies", searchHandler.FindMovies)
+	r.GET("/api/search", searchHandler.SearchMovies)
+	r.GET("/api/search/suggest", searchHandler.Suggest)
+	r.GET("/api/people/:id", peopleHandler.GetPerson)
+	r.GET("/api/images/:size/*path", imageHandler.GetImage)
+
 	// Protected routes
 	protected := r.Group("/api")
 	protected.Use(authHandler.AuthMiddleware())
 	{
 		protected.GET("/profile", authHandler.GetProfile)
+		protected.PATCH("/profile", authHandler.UpdateProfile)
+		protected.GET("/profile/services", availabilityHandler.GetUserServices)
+		protected.PUT("/profile/services", availabilityHandler.SetUserServices)
+		protected.GET("/watch-providers/:movie_id", availabilityHandler.GetWatchProviders)
+		protected.GET("/notifications", notificationHandler.GetNotifications)
+		protected.POST("/notifications/read-all", notificationHandler.MarkAllNotificationsRead)
+		protected.POST("/notifications/:id/read", notificationHandler.MarkNotificationRead)
 		protected.POST("/watchlist", watchlistHandler.AddToWatchlist)
 		protected.DELETE("/watchlist", watchlistHandler.RemoveFromWatchlist)
 		protected.GET("/watchlist", watchlistHandler.GetWatchlist)
+		protected.POST("/watchlist/bulk", watchlistHandler.BulkWatchlist)
+		protected.POST("/watchlist/undo", watchlistHandler.UndoRemove)
+		protected.POST("/watchlist/import", importHandler.Import)
+		protected.GET("/watchlist/export", watchlistHandler.ExportWatchlist)
+		protected.GET("/watchlist/tags", watchlistHandler.GetWatchlistTags)
+		protected.GET("/watchlist/changes", watchlistHandler.GetWatchlistChanges)
+		protected.PATCH("/watchlist/order", watchlistHandler.ReorderWatchlist)
+		protected.PATCH("/watchlist/:movie_id", watchlistHandler.UpdateWatchlistItem)
+		protected.POST("/lists", listHandler.CreateList)
+		protected.GET("/lists", listHandler.GetLists)
+		protected.GET("/lists/:id", listHandler.GetList)
+		protected.PATCH("/lists/:id", listHandler.RenameList)
+		protected.DELETE("/lists/:id", listHandler.DeleteList)
+		protected.GET("/lists/:id/sharing", listHandler.GetSharing)
+		protected.PUT("/lists/:id/sharing", listHandler.SetSharing)
+		protected.POST("/lists/:id/sharing/rotate", listHandler.RotatePublicSlug)
+		protected.DELETE("/lists/:id/sharing", listHandler.RevokePublicSlug)
+		protected.POST("/lists/:id/invites", listHandler.CreateInvite)
+		protected.PATCH("/lists/:id/members/:user_id", listHandler.UpdateMember)
+		protected.DELETE("/lists/:id/members/:user_id", listHandler.RemoveMember)
+		protected.POST("/invites/accept", listHandler.AcceptInvite)
+		protected.POST("/groups/:id/pick", groupHandler.Pick)
+		protected.GET("/tv/continue-watching", tvHandler.GetContinueWatching)
+		protected.GET("/tv/:show_id/progress", tvHandler.GetShowProgress)
+		protected.POST("/tv/:show_id/seasons/:season/watched", tvHandler.MarkSeasonWatched)
+		protected.DELETE("/tv/:show_id/seasons/:season/watched", tvHandler.UnmarkSeasonWatched)
+		protected.POST("/tv/:show_id/seasons/:season/episodes/:episode/watched", tvHandler.MarkEpisodeWatched)
+		protected.DELETE("/tv/:show_id/seasons/:season/episodes/:episode/watched", tvHandler.UnmarkEpisodeWatched)
+	}
+
+	// Catalog editing and cache statistics (admins only)
+	admin := r.Group("/api/admin")
+	admin.Use(authHandler.AuthMiddleware(), authHandler.RequireAdmin())
+	{
+		admin.GET("/movies", adminMovieHandler.ListMovies)
+		admin.POST("/movies", adminMovieHandler.CreateMovie)
+		admin.GET("/movies/:id", adminMovieHandler.GetMovie)
+		admin.PATCH("/movies/:id", adminMovieHandler.UpdateMovie)
+		admin.DELETE("/movies/:id", adminMovieHandler.DeleteMovie)
+		admin.POST("/movies/:id/restore", adminMovieHandler.RestoreMovie)
+		admin.GET("/movies/:id/history", adminMovieHandler.GetMovieHistory)
+		admin.GET("/catalog/cache-stats", catalogHandler.GetCacheStats)
 	}
 
 	// Rate limiter for chat endpoint: 1 req/sec per client
@@ -71,17 +268,17 @@ func SetupRouter() *gin.Engine {
 			api.POST("/chat", tollbooth_gin.LimitHandler(limiter), chatHandler.HandleChat)
 		}
 		r.GET("/health", func(c *gin.Context) { c.JSON(200, gin.H{"status": "ok", "provider": "gemini"}) })
-	r.GET("/debug", func(c *gin.Context) { 
-		model := os.Getenv("GEMINI_MODEL")
-		if model == "" {
-			model = "gemini-1.5-pro (default)"
-		}
-		c.JSON(200, gin.H{
-			"gemini_api_key_set": os.Getenv("GEMINI_API_KEY") != "",
-			"gemini_model": model,
-			"gemini_system_prompt_set": os.Getenv("GEMINI_SYSTEM_PROMPT") != "",
+		r.GET("/debug", func(c *gin.Context) {
+			model := os.Getenv("GEMINI_MODEL")
+			if model == "" {
+				model = "gemini-1.5-pro (default)"
+			}
+			c.JSON(200, gin.H{
+				"gemini_api_key_set":       os.Getenv("GEMINI_API_KEY") != "",
+				"gemini_model":             model,
+				"gemini_system_prompt_set": os.Getenv("GEMINI_SYSTEM_PROMPT") != "",
+			})
 		})
-	})
 		return r
 	}
 
@@ -95,3 +292,40 @@ func SetupRouter() *gin.Engine {
 
 	return r
 }
+
+// envInt reads a non-negative integer setting, using def when unset or invalid
+func envInt(name string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || v < 0 {
+		return def
+	}
+	return v
+}
+
+// envDuration reads a positive duration setting such as "6h", using def when unset or invalid
+func envDuration(name string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || v <= 0 {
+		return def
+	}
+	return v
+}
+
+// envFloat reads a non-negative number setting, using def when unset or invalid
+func envFloat(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil || v < 0 {
+		return def
+	}
+	return v
+}
+
+// CatalogSyncOptionsFromEnv reads the catalog sync settings. The export path
+// (CATALOG_SYNC_EXPORT) is empty when the sync isn't configured.
+func CatalogSyncOptionsFromEnv() (string, usecase.CatalogSyncOptions) {
+	return os.Getenv("CATALOG_SYNC_EXPORT"), usecase.CatalogSyncOptions{
+		MinPopularity: envFloat("CATALOG_SYNC_MIN_POPULARITY", 0),
+		MaxFetches:    envInt("CATALOG_SYNC_MAX_FETCHES", 5000),
+		RefreshAfter:  envDuration("CATALOG_SYNC_REFRESH_AFTER", 30*24*time.Hour),
+	}
+}
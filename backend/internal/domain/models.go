@@ -1,36 +1,876 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
-	ID        uint   `gorm:"primaryKey"`
-	Username  string `gorm:"uniqueIndex;size:100;not null"`
-	Email     string `gorm:"uniqueIndex;size:200"`
-	Password  string `gorm:"not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Watchlist []WatchlistItem `gorm:"foreignKey:UserID"`
+	ID             uint   `gorm:"primaryKey"`
+	Username       string `gorm:"uniqueIndex;size:100;not null"`
+	Email          string `gorm:"uniqueIndex;size:200"`
+	Password       string `gorm:"not null"`
+	Region         string `gorm:"size:2;not null;default:US"` // ISO 3166-1 code, used for release dates
+	EmailReminders bool   `gorm:"not null;default:false"`     // release reminders by email as well as in-app; opt-in
+	Role           string `gorm:"size:20;not null;default:user"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Watchlist      []WatchlistItem `gorm:"foreignKey:UserID"`
 	// optionally add Watched []WatchedItem
 }
 
+// User roles. Admins can edit the movie catalog.
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
 type Movie struct {
-	ID          uint   `gorm:"primaryKey"`
-	Title       string `gorm:"uniqueIndex;size:300;not null"`
-	Description string `gorm:"type:text"`
-	Year        int
-	Genres      string `gorm:"size:200"` // CSV for simplicity
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID uint `gorm:"primaryKey"`
+	// External IDs identify a title across sources. TMDB IDs are only unique
+	// per media type; titles aren't unique at all (remakes share them).
+	MediaType     string  `gorm:"uniqueIndex:idx_movies_tmdb;size:10;not null;default:movie"` // "movie" or "tv"
+	TMDBID        *uint   `gorm:"uniqueIndex:idx_movies_tmdb"`
+	IMDbID        *string `gorm:"column:imdb_id;uniqueIndex;size:20"`
+	Title         string  `gorm:"size:300;not null"`
+	OriginalTitle string  `gorm:"size:300"` // title in the original language, when different
+	Description   string  `gorm:"type:text"`
+	Year          int
+	Genres        []Genre     `gorm:"many2many:movie_genres"`
+	CastNames     StringArray `gorm:"type:text[]"` // top-billed cast, for search
+	Credits       []Credit    `gorm:"foreignKey:MovieID"`
+	Popularity    float64     `gorm:"index"` // TMDB popularity as of the last sync
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"` // hidden by an admin; the sync leaves it hidden
+}
+
+// MoviePatch holds an admin's edits to a movie; nil fields are left
+// unchanged. An empty IMDbID or a zero TMDBID clears it.
+type MoviePatch struct {
+	MediaType     *string
+	TMDBID        *uint
+	IMDbID        *string
+	Title         *string
+	OriginalTitle *string
+	Description   *string
+	Year          *int
+	GenreIDs      *[]uint
+	CastNames     *[]string
+}
+
+// MovieListQuery pages through stored movies, newest first
+type MovieListQuery struct {
+	Title          string // case-insensitive substring, "" for any
+	IncludeDeleted bool
+	Limit          int
+	Offset         int
+}
+
+// Movie audit actions
+const (
+	MovieAuditCreated  = "created"
+	MovieAuditUpdated  = "updated"
+	MovieAuditDeleted  = "deleted"
+	MovieAuditRestored = "restored"
+)
+
+// MovieAudit records who changed a movie through the admin API, and when.
+// Changes is a JSON object mapping each edited field to its old and new value.
+type MovieAudit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	MovieID   uint      `gorm:"index;not null" json:"movie_id"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	Action    string    `gorm:"size:10;not null" json:"action"`
+	Changes   string    `gorm:"type:jsonb" json:"-"`
+	CreatedAt time.Time `json:"at"`
+}
+
+// FieldChange is one field's edit in a MovieAudit
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Person is someone credited on a stored movie. IDs are TMDB's.
+type Person struct {
+	ID                 uint      `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Name               string    `gorm:"size:200;not null" json:"name"`
+	ProfilePath        string    `gorm:"size:100" json:"profile_path,omitempty"`
+	KnownForDepartment string    `gorm:"size:50" json:"known_for_department,omitempty"`
+	CreatedAt          time.Time `json:"-"`
+	UpdatedAt          time.Time `json:"-"`
+}
+
+// Credit roles
+const (
+	CreditCast = "cast"
+	CreditCrew = "crew"
+)
+
+// Credit puts a person on a movie, in the cast (Character, Order) or the
+// crew (Department, Job). Someone can have several credits on one movie.
+type Credit struct {
+	ID         uint    `gorm:"primaryKey"`
+	MovieID    uint    `gorm:"index;not null"`
+	PersonID   uint    `gorm:"index;not null"`
+	Role       string  `gorm:"size:4;not null"`
+	Character  string  `gorm:"size:500"`
+	Order      int     `gorm:"column:billing_order"` // cast billing, 0 first
+	Department string  `gorm:"size:50"`
+	Job        string  `gorm:"size:100"`
+	Person     *Person `gorm:"foreignKey:PersonID"`
+	Movie      *Movie  `gorm:"foreignKey:MovieID"`
+}
+
+// MovieSearchQuery is a full-text search over stored movies. Query uses web
+// search syntax: quoted phrases, OR, and -word to exclude.
+type MovieSearchQuery struct {
+	Query    string
+	Year     int    // 0 for any year
+	GenreIDs []uint // movies must have all of them
+	Limit    int
+	Offset   int
+}
+
+// MovieSearchHit is a matching movie with its relevance and highlighted
+// text. Highlights are HTML-escaped, with matches wrapped in <mark>.
+type MovieSearchHit struct {
+	Movie          Movie
+	Rank           float64
+	TitleHighlight string
+	Snippet        string // best-matching overview fragments
+}
+
+// TitleSuggestion is an autocomplete match among stored movies. Score blends
+// how well the title matches with the movie's popularity.
+type TitleSuggestion struct {
+	ID        uint    `json:"id"`
+	MediaType string  `json:"media_type"`
+	TMDBID    *uint   `json:"tmdb_id,omitempty"`
+	Title     string  `json:"title"`
+	Year      int     `json:"year,omitempty"`
+	Score     float64 `json:"score"`
 }
 
+// PersonSuggestion is an autocomplete match among people. Movies is how
+// many stored movies credit them.
+type PersonSuggestion struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	ProfilePath string  `json:"profile_path,omitempty"`
+	Movies      int     `json:"movies"`
+	Score       float64 `json:"score"`
+}
+
+// Suggestions answers an autocomplete request. Partial is set when a lookup
+// didn't finish within the latency budget and its list was left empty.
+type Suggestions struct {
+	Titles  []TitleSuggestion  `json:"titles"`
+	People  []PersonSuggestion `json:"people"`
+	Partial bool               `json:"partial"`
+}
+
+// Genre is a TMDB genre. IDs are TMDB's, so they can be passed straight to
+// /api/catalog/discover. Movie and TV say which of TMDB's lists it is on.
+type Genre struct {
+	ID    uint   `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Name  string `gorm:"size:50;not null" json:"name"`
+	Movie bool   `gorm:"not null;default:false" json:"movie"`
+	TV    bool   `gorm:"not null;default:false" json:"tv"`
+}
+
+// MovieGenre is the join table behind Movie.Genres
+type MovieGenre struct {
+	MovieID uint `gorm:"primaryKey"`
+	GenreID uint `gorm:"primaryKey;index"`
+}
+
+// WatchlistItem is a title on a list. UserID is the list owner and ListID is 0
+// for their personal watchlist; AddedByID records who put it there. Removed
+// items are soft-deleted so they can be restored, then purged later.
 type WatchlistItem struct {
-	ID      uint `gorm:"primaryKey"`
-	UserID  uint `gorm:"index:idx_user_movie,unique;not null"`
-	MovieID uint `gorm:"index:idx_user_movie,unique;not null"`
-	AddedAt time.Time
+	ID        uint    `gorm:"primaryKey"`
+	UserID    uint    `gorm:"index:idx_user_list_movie,unique;not null"`
+	ListID    uint    `gorm:"index:idx_user_list_movie,unique;not null;default:0"`
+	MovieID   uint    `gorm:"index:idx_user_list_movie,unique;not null"`
+	AddedByID uint    `gorm:"index"`
+	Rank      float64 `gorm:"not null;default:0"` // fractional position, lowest first
+	Priority  string  `gorm:"size:20;not null;default:someday"`
+	Notes     string  `gorm:"type:text"`
+	AddedAt   time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	Tags      []WatchlistTag `gorm:"foreignKey:WatchlistItemID;constraint:OnDelete:CASCADE"`
+}
+
+// ListRef identifies a watchlist: a user's personal list (ListID 0) or a
+// shared list, which is always addressed through its owner
+type ListRef struct {
+	OwnerID uint
+	ListID  uint
+}
+
+// Watchlist is a named list that its owner can share with other users.
+// A public list can also be read by anyone through its PublicSlug.
+type Watchlist struct {
+	ID         uint              `gorm:"primaryKey" json:"id"`
+	OwnerID    uint              `gorm:"index;not null" json:"owner_id"`
+	Name       string            `gorm:"size:100;not null" json:"name"`
+	Visibility string            `gorm:"size:10;not null;default:private" json:"visibility"`
+	PublicSlug *string           `gorm:"uniqueIndex;size:32" json:"public_slug,omitempty"`
+	ShareNotes bool              `gorm:"not null;default:false" json:"share_notes"` // show notes and tags on the public link
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	Members    []WatchlistMember `gorm:"foreignKey:WatchlistID" json:"members,omitempty"`
+}
+
+// PersonalListSharing holds the sharing settings of a user's personal
+// watchlist, which has no Watchlist row of its own
+type PersonalListSharing struct {
+	UserID     uint    `gorm:"primaryKey"`
+	Visibility string  `gorm:"size:10;not null;default:private"`
+	PublicSlug *string `gorm:"uniqueIndex;size:32"`
+	ShareNotes bool    `gorm:"not null;default:false"`
+	UpdatedAt  time.Time
+}
+
+// PersonalListName is what a user's personal watchlist is called where a
+// list needs a name, such as on its public link
+const PersonalListName = "Watchlist"
+
+// List visibility
+const (
+	VisibilityPrivate = "private"
+	VisibilityPublic  = "public"
+)
+
+// ListAccess is a list together with the caller's role on it
+type ListAccess struct {
+	Watchlist
+	Role string `json:"role"`
+}
+
+// List roles, in increasing order of access
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleOwner  = "owner"
+)
+
+// RoleAtLeast reports whether role grants at least the access of need
+func RoleAtLeast(role, need string) bool {
+	rank := map[string]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+	return rank[role] >= rank[need] && rank[role] > 0
+}
+
+// WatchlistMember grants a user editor or viewer access to a shared list
+type WatchlistMember struct {
+	WatchlistID uint      `gorm:"primaryKey" json:"-"`
+	UserID      uint      `gorm:"primaryKey" json:"user_id"`
+	Username    string    `gorm:"-" json:"username,omitempty"`
+	Role        string    `gorm:"size:10;not null" json:"role"`
+	CreatedAt   time.Time `json:"joined_at"`
+}
+
+// WatchlistInvite is a one-time token that adds its bearer to a list.
+// Only a SHA-256 hash of the token is stored.
+type WatchlistInvite struct {
+	ID           uint   `gorm:"primaryKey"`
+	WatchlistID  uint   `gorm:"index;not null"`
+	TokenHash    string `gorm:"uniqueIndex;size:64;not null"`
+	Role         string `gorm:"size:10;not null"`
+	InvitedByID  uint   `gorm:"not null"`
+	ExpiresAt    time.Time
+	AcceptedByID *uint
+	AcceptedAt   *time.Time
+	CreatedAt    time.Time
+}
+
+// WatchlistTag is a user-defined label on a watchlist item, stored without the leading '#'
+type WatchlistTag struct {
+	ID              uint   `gorm:"primaryKey"`
+	WatchlistItemID uint   `gorm:"index:idx_item_tag,unique;not null"`
+	UserID          uint   `gorm:"index;not null"`
+	Name            string `gorm:"index:idx_item_tag,unique;size:50;not null"`
+}
+
+// TagCount is a tag name with the number of items carrying it
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// WatchlistFilter narrows a watchlist listing; zero values match everything.
+// Tags are matched against the items, ProviderIDs against cached watch offers
+// and everything else against cached metadata.
+type WatchlistFilter struct {
+	Tags        []string // items must carry all of these
+	Genre       string
+	MediaType   string
+	YearFrom    int
+	YearTo      int
+	RuntimeMin  int
+	RuntimeMax  int
+	ProviderIDs []int  // items must be on a subscription to one of these in Region
+	Region      string // ISO 3166-1 code for ProviderIDs
+}
+
+// NeedsMetadata reports whether the filter reads cached metadata
+func (f WatchlistFilter) NeedsMetadata() bool {
+	return f.Genre != "" || f.MediaType != "" || f.YearFrom > 0 || f.YearTo > 0 ||
+		f.RuntimeMin > 0 || f.RuntimeMax > 0 || f.NeedsAvailability()
+}
+
+// NeedsAvailability reports whether the filter reads cached watch offers
+func (f WatchlistFilter) NeedsAvailability() bool {
+	return len(f.ProviderIDs) > 0
+}
+
+// Watchlist sort keys
+const (
+	SortRank   = "rank"
+	SortAdded  = "added"
+	SortTitle  = "title"
+	SortYear   = "year"
+	SortRating = "rating"
+)
+
+// WatchlistQuery requests one page of a watchlist
+type WatchlistQuery struct {
+	List   ListRef
+	Filter WatchlistFilter
+	Sort   string
+	Desc   bool
+	Cursor string // opaque, taken from a previous page's NextCursor
+	Limit  int
+}
+
+// WatchlistPage is one page of watchlist items
+type WatchlistPage struct {
+	Items      []WatchlistItem
+	Total      int64  // items matching the filter across all pages
+	NextCursor string // empty on the last page
+}
+
+// Watchlist change kinds
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeUpdated = "updated" // priority, notes, tags or position changed
+)
+
+// WatchlistChange is one entry in a list's change log. Versions count up by
+// one per change to the list, so clients can sync from the last one they saw.
+type WatchlistChange struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	UserID    uint      `gorm:"index:idx_change_list_version,unique;not null" json:"-"`
+	ListID    uint      `gorm:"index:idx_change_list_version,unique;not null;default:0" json:"-"`
+	Version   int64     `gorm:"index:idx_change_list_version,unique;not null" json:"version"`
+	MovieID   uint      `gorm:"not null" json:"movie_id"`
+	Kind      string    `gorm:"size:10;not null" json:"kind"`
+	CreatedAt time.Time `json:"at"`
+}
+
+// WatchlistVersion is the latest change version of a list
+type WatchlistVersion struct {
+	UserID  uint `gorm:"primaryKey;autoIncrement:false"`
+	ListID  uint `gorm:"primaryKey;autoIncrement:false"`
+	Version int64
+}
+
+// Watchlist item priorities
+const (
+	PriorityMustWatch = "must-watch"
+	PrioritySomeday   = "someday"
+)
+
+// Bulk watchlist actions and per-item outcomes
+const (
+	BulkAdd    = "add"
+	BulkRemove = "remove"
+
+	BulkStatusAdded    = "added"
+	BulkStatusExists   = "exists"
+	BulkStatusRemoved  = "removed"
+	BulkStatusRestored = "restored"
+	BulkStatusNotFound = "not_found"
+)
+
+// BulkResult reports what a bulk operation did to one title
+type BulkResult struct {
+	MovieID uint   `json:"movie_id"`
+	Status  string `json:"status"`
+}
+
+// WatchlistItemPatch holds optional edits to a watchlist item; nil fields are left unchanged
+type WatchlistItemPatch struct {
+	Priority *string
+	Notes    *string
+	Tags     *[]string // replaces the item's tags when set
+}
+
+// MovieMetadata caches TMDB details for IDs stored in watchlist_items.movie_id
+// so watchlists can be hydrated without a TMDB round trip per item.
+type MovieMetadata struct {
+	TMDBID      uint   `gorm:"primaryKey;autoIncrement:false"`
+	MediaType   string `gorm:"size:10"` // "movie" or "tv"
+	IMDbID      string `gorm:"size:20"`
+	Title       string `gorm:"size:300"`
+	PosterPath  string `gorm:"size:200"`
+	ReleaseYear int
+	Runtime     int     // minutes (episode runtime for TV)
+	Rating      float64 // TMDB vote average
+	Genres      string  `gorm:"size:200"` // CSV for simplicity
+	FetchedAt   time.Time
+}
+
+func (MovieMetadata) TableName() string { return "movie_metadata" }
+
+// DiaryEntry records a viewing of a title, keyed by TMDB ID like WatchlistItem
+type DiaryEntry struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"index:idx_diary_viewing,unique;not null"`
+	MovieID   uint      `gorm:"index:idx_diary_viewing,unique;not null"`
+	WatchedOn time.Time `gorm:"type:date;index:idx_diary_viewing,unique;not null"`
+	Rating    float64   // 0-10 scale, 0 when unrated
+	Rewatch   bool
+	Review    string `gorm:"type:text"`
+	Source    string `gorm:"size:20"` // where the entry came from, e.g. "letterboxd"
+	CreatedAt time.Time
 }
 
 // Chat domain interfaces
 type ChatService interface {
 	GenerateReply(prompt string) (string, error)
 }
+
+// MetadataProvider looks up title details from an external catalog (TMDB)
+type MetadataProvider interface {
+	FetchMetadata(tmdbID uint) (*MovieMetadata, error) // nil if the catalog has no such title
+}
+
+// TitleMatcher resolves titles exported from other services to TMDB IDs
+type TitleMatcher interface {
+	// FindByIMDbID returns nil when TMDB doesn't know the ID
+	FindByIMDbID(imdbID string) (*MovieMetadata, error)
+	// SearchTitle searches movies or TV shows; year 0 searches all years
+	SearchTitle(mediaType, title string, year int) ([]MovieMetadata, error)
+}
+
+// ReleaseDate is when a movie comes out in one region (ISO 3166-1 code)
+type ReleaseDate struct {
+	TMDBID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	Region    string    `gorm:"primaryKey;size:2"`
+	Date      time.Time `gorm:"type:date;not null"`
+	FetchedAt time.Time
+}
+
+// Notification kinds
+const (
+	NotificationReleaseSoon = "release_soon"
+	NotificationReleaseDay  = "release_day"
+)
+
+// Notification is an in-app message to a user. The unique index makes each
+// reminder for a given release date fire once, however often the job runs.
+type Notification struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"index:idx_notification_once,unique;index;not null" json:"-"`
+	Kind        string     `gorm:"index:idx_notification_once,unique;size:20;not null" json:"kind"`
+	MovieID     uint       `gorm:"index:idx_notification_once,unique;not null" json:"movie_id"`
+	ReleaseDate time.Time  `gorm:"index:idx_notification_once,unique;type:date;not null" json:"release_date"`
+	Title       string     `gorm:"size:300" json:"title"`
+	Message     string     `gorm:"size:500" json:"message"`
+	ReadAt      *time.Time `json:"read_at"`
+	EmailedAt   *time.Time `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ReminderCandidate is a watchlisted title and the user to remind about it
+type ReminderCandidate struct {
+	UserID         uint
+	Username       string
+	Email          string
+	Region         string
+	EmailReminders bool
+	MovieID        uint
+}
+
+// PendingEmail is a notification that hasn't been emailed yet and its recipient
+type PendingEmail struct {
+	To           ReminderCandidate
+	Notification Notification
+}
+
+// ProfilePatch holds optional profile edits; nil fields are left unchanged
+type ProfilePatch struct {
+	Region         *string
+	EmailReminders *bool
+}
+
+// ReleaseDateProvider looks up a movie's release dates in every region
+type ReleaseDateProvider interface {
+	FetchReleaseDates(tmdbID uint) ([]ReleaseDate, error)
+}
+
+// NotificationSender delivers a notification outside the app, e.g. by email
+type NotificationSender interface {
+	Send(to ReminderCandidate, n Notification) error
+}
+
+// Watch offer types, as named by TMDB
+const (
+	OfferStream = "flatrate"
+	OfferFree   = "free"
+	OfferAds    = "ads"
+	OfferRent   = "rent"
+	OfferBuy    = "buy"
+)
+
+// SubscriptionOfferTypes are the offer types that count as "on a service":
+// included with a subscription or free to watch
+var SubscriptionOfferTypes = []string{OfferStream, OfferFree, OfferAds}
+
+// WatchOffer is one way to watch a title in a region, e.g. streaming on a service
+type WatchOffer struct {
+	TMDBID       uint   `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Region       string `gorm:"primaryKey;size:2;index" json:"-"`
+	ProviderID   int    `gorm:"primaryKey;autoIncrement:false;index" json:"provider_id"`
+	Type         string `gorm:"primaryKey;size:10" json:"type"`
+	ProviderName string `gorm:"size:100" json:"provider_name"`
+	LogoPath     string `gorm:"size:200" json:"logo_path,omitempty"`
+	Priority     int    `json:"-"` // provider's display order in the region
+}
+
+// WatchOffersFetch records when a title's offers were last fetched, so that
+// titles with no offers anywhere aren't looked up again on every request
+type WatchOffersFetch struct {
+	TMDBID    uint `gorm:"primaryKey;autoIncrement:false"`
+	FetchedAt time.Time
+}
+
+// UserService is a streaming service the user subscribes to
+type UserService struct {
+	UserID     uint `gorm:"primaryKey;autoIncrement:false"`
+	ProviderID int  `gorm:"primaryKey;autoIncrement:false"`
+}
+
+// WatchProviderService looks up where a title can be watched, in every region at once
+type WatchProviderService interface {
+	FetchWatchOffers(tmdbID uint, mediaType string) ([]WatchOffer, error)
+}
+
+// Group picks: a group is a shared list's owner and members, and a pick looks
+// across their personal watchlists
+const (
+	PickIntersection = "intersection" // titles on every member's watchlist
+	PickOverlap      = "overlap"      // titles on at least MinMembers watchlists, ranked by weight
+)
+
+// PickMember is someone whose watchlist counts towards a pick
+type PickMember struct {
+	UserID   uint
+	Username string
+}
+
+// PickOptions constrains a group pick; zero values mean no constraint
+type PickOptions struct {
+	Mode           string
+	MinMembers     int // overlap mode only
+	MaxRuntime     int // minutes; titles with unknown runtime are kept
+	ExcludeGenres  []string
+	MediaType      string
+	IncludeWatched bool // keep titles a member has already logged in their diary
+	Limit          int
+}
+
+// PickCandidate is a ranked title and why it was picked
+type PickCandidate struct {
+	MovieID     uint     `json:"movie_id"`
+	MediaType   string   `json:"media_type,omitempty"`
+	Title       string   `json:"title"`
+	PosterPath  string   `json:"poster_path,omitempty"`
+	Year        int      `json:"year,omitempty"`
+	Runtime     int      `json:"runtime,omitempty"`
+	Rating      float64  `json:"rating,omitempty"`
+	Genres      []string `json:"genres"`
+	Score       float64  `json:"score"`
+	WantedBy    []string `json:"wanted_by"`
+	MustWatchBy []string `json:"must_watch_by,omitempty"`
+	Reasons     []string `json:"reasons"`
+}
+
+// TVShow is cached TMDB data about a show; its seasons are stored as TVSeason rows
+type TVShow struct {
+	TMDBID     uint      `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Name       string    `gorm:"size:300" json:"name"`
+	PosterPath string    `gorm:"size:200" json:"poster_path,omitempty"`
+	Status     string    `gorm:"size:50" json:"status,omitempty"` // e.g. "Returning Series" or "Ended"
+	FetchedAt  time.Time `json:"-"`
+}
+
+// TVSeason is a season of a show. Season 0 holds specials.
+type TVSeason struct {
+	ShowID            uint       `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	SeasonNumber      int        `gorm:"primaryKey;autoIncrement:false" json:"season_number"`
+	Name              string     `gorm:"size:300" json:"name"`
+	EpisodeCount      int        `json:"episode_count"`
+	AirDate           *time.Time `gorm:"type:date" json:"air_date,omitempty"`
+	EpisodesFetchedAt *time.Time `json:"-"` // nil until the season's episodes are cached
+}
+
+// TVEpisode is an episode from cached TMDB season data
+type TVEpisode struct {
+	ShowID        uint       `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	SeasonNumber  int        `gorm:"primaryKey;autoIncrement:false" json:"season_number"`
+	EpisodeNumber int        `gorm:"primaryKey;autoIncrement:false" json:"episode_number"`
+	Name          string     `gorm:"size:300" json:"name"`
+	AirDate       *time.Time `gorm:"type:date" json:"air_date,omitempty"`
+	Runtime       int        `json:"runtime,omitempty"`
+	StillPath     string     `gorm:"size:200" json:"still_path,omitempty"`
+}
+
+// Aired reports whether the episode has aired by now; episodes without a date haven't
+func (e TVEpisode) Aired(now time.Time) bool {
+	return e.AirDate != nil && !e.AirDate.After(now)
+}
+
+// EpisodeProgress records that a user watched an episode
+type EpisodeProgress struct {
+	UserID        uint `gorm:"primaryKey;autoIncrement:false"`
+	ShowID        uint `gorm:"primaryKey;autoIncrement:false;index"`
+	SeasonNumber  int  `gorm:"primaryKey;autoIncrement:false"`
+	EpisodeNumber int  `gorm:"primaryKey;autoIncrement:false"`
+	WatchedAt     time.Time
+}
+
+// ShowActivity summarises a user's progress on one show
+type ShowActivity struct {
+	ShowID        uint
+	Watched       int
+	LastWatchedAt time.Time
+}
+
+// EpisodeStatus is an episode and whether the user has watched it
+type EpisodeStatus struct {
+	TVEpisode
+	Watched   bool       `json:"watched"`
+	WatchedAt *time.Time `json:"watched_at,omitempty"`
+}
+
+// SeasonProgress is a season with the user's progress through it
+type SeasonProgress struct {
+	TVSeason
+	Watched  int             `json:"watched"`
+	Episodes []EpisodeStatus `json:"episodes"`
+}
+
+// ShowProgress is a user's progress through a show. NextUp is the first
+// aired, unwatched episode after the furthest one watched; nil when caught up.
+type ShowProgress struct {
+	Show          TVShow           `json:"show"`
+	Watched       int              `json:"watched"`
+	Total         int              `json:"total"` // episodes outside specials
+	NextUp        *TVEpisode       `json:"next_up"`
+	LastWatchedAt *time.Time       `json:"last_watched_at,omitempty"`
+	Seasons       []SeasonProgress `json:"seasons,omitempty"`
+}
+
+// TVProvider looks up shows and their seasons
+type TVProvider interface {
+	FetchShow(showID uint) (*TVShow, []TVSeason, error) // nil show when TMDB doesn't know it
+	FetchSeason(showID uint, season int) ([]TVEpisode, error)
+}
+
+// Catalog proxy: TMDB listings and details served through the backend so the
+// API credential never reaches the browser. JSON names follow TMDB's, so
+// clients can switch from TMDB to the proxy without reshaping responses.
+
+// CatalogItem is a movie, show or person in a listing. MediaType is filled
+// in even where TMDB leaves it out (e.g. /movie/popular).
+type CatalogItem struct {
+	ID            uint    `json:"id"`
+	MediaType     string  `json:"media_type,omitempty"`
+	Title         string  `json:"title,omitempty"`
+	Name          string  `json:"name,omitempty"`
+	OriginalTitle string  `json:"original_title,omitempty"`
+	OriginalName  string  `json:"original_name,omitempty"`
+	Overview      string  `json:"overview,omitempty"`
+	PosterPath    string  `json:"poster_path,omitempty"`
+	BackdropPath  string  `json:"backdrop_path,omitempty"`
+	ProfilePath   string  `json:"profile_path,omitempty"`
+	ReleaseDate   string  `json:"release_date,omitempty"`
+	FirstAirDate  string  `json:"first_air_date,omitempty"`
+	VoteAverage   float64 `json:"vote_average"`
+	VoteCount     int     `json:"vote_count"`
+	Popularity    float64 `json:"popularity"`
+	GenreIDs      []int   `json:"genre_ids,omitempty"`
+}
+
+// CatalogPage is one page of a listing
+type CatalogPage struct {
+	Page         int           `json:"page"`
+	Results      []CatalogItem `json:"results"`
+	TotalPages   int           `json:"total_pages"`
+	TotalResults int           `json:"total_results"`
+}
+
+type CatalogGenre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type CatalogVideo struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Site        string `json:"site"`
+	Type        string `json:"type"`
+	Official    bool   `json:"official"`
+	PublishedAt string `json:"published_at,omitempty"`
+}
+
+type CatalogVideos struct {
+	Results []CatalogVideo `json:"results"`
+}
+
+type CatalogCastMember struct {
+	ID                 uint   `json:"id"`
+	Name               string `json:"name"`
+	Character          string `json:"character,omitempty"`
+	ProfilePath        string `json:"profile_path,omitempty"`
+	KnownForDepartment string `json:"known_for_department,omitempty"`
+	Order              int    `json:"order"`
+}
+
+type CatalogCrewMember struct {
+	ID                 uint   `json:"id"`
+	Name               string `json:"name"`
+	Job                string `json:"job"`
+	Department         string `json:"department"`
+	ProfilePath        string `json:"profile_path,omitempty"`
+	KnownForDepartment string `json:"known_for_department,omitempty"`
+}
+
+type CatalogCredits struct {
+	Cast []CatalogCastMember `json:"cast"`
+	Crew []CatalogCrewMember `json:"crew"`
+}
+
+// CatalogDetails is a movie or show with its credits and videos
+type CatalogDetails struct {
+	CatalogItem
+	Tagline          string         `json:"tagline,omitempty"`
+	Status           string         `json:"status,omitempty"`
+	IMDbID           string         `json:"imdb_id,omitempty"`
+	Runtime          int            `json:"runtime,omitempty"`
+	EpisodeRunTime   []int          `json:"episode_run_time,omitempty"`
+	NumberOfSeasons  int            `json:"number_of_seasons,omitempty"`
+	NumberOfEpisodes int            `json:"number_of_episodes,omitempty"`
+	Genres           []CatalogGenre `json:"genres"`
+	Credits          CatalogCredits `json:"credits"`
+	Videos           CatalogVideos  `json:"videos"`
+}
+
+type CatalogImage struct {
+	FilePath    string  `json:"file_path"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	AspectRatio float64 `json:"aspect_ratio"`
+	Language    string  `json:"iso_639_1,omitempty"`
+	VoteAverage float64 `json:"vote_average"`
+}
+
+type CatalogImages struct {
+	Backdrops []CatalogImage `json:"backdrops"`
+	Posters   []CatalogImage `json:"posters"`
+	Logos     []CatalogImage `json:"logos"`
+}
+
+// DiscoverQuery filters /discover; zero fields are left out
+type DiscoverQuery struct {
+	MediaType    string // movie or tv
+	Genres       []int  // titles must have all of them
+	Year         int
+	ReleasedFrom string // YYYY-MM-DD
+	ReleasedTo   string
+	SortBy       string // e.g. popularity.desc
+	Page         int
+}
+
+// CatalogProvider serves the catalog endpoints. Media types are "movie" or
+// "tv"; trending and search also mix in people.
+type CatalogProvider interface {
+	Trending(mediaType, window string, page int) (*CatalogPage, error)
+	// List returns a curated list such as popular or top_rated
+	List(mediaType, list string, page int) (*CatalogPage, error)
+	Discover(q DiscoverQuery) (*CatalogPage, error)
+	Search(query string, page int) (*CatalogPage, error)
+	// Details, Videos and Images return nil when TMDB doesn't know the title
+	Details(mediaType string, id uint) (*CatalogDetails, error)
+	Videos(mediaType string, id uint) (*CatalogVideos, error)
+	Images(mediaType string, id uint) (*CatalogImages, error)
+	// Genres returns TMDB's genre list for movies or TV
+	Genres(mediaType string) ([]CatalogGenre, error)
+}
+
+// CacheEntry is a row of the shared cache table
+type CacheEntry struct {
+	Key       string    `gorm:"primaryKey;column:cache_key" json:"key"`
+	Value     []byte    `json:"-"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+}
+
+// CacheStore is a cache shared between server instances, such as a database
+// table or Redis. Values are opaque bytes.
+type CacheStore interface {
+	GetCache(key string) ([]byte, bool, error) // false when missing or expired
+	SetCache(key string, value []byte, ttl time.Duration) error
+}
+
+// ImageOrigin fetches a full-size source image by its TMDB file path, e.g.
+// /wo2hJpn04vbtmh0B9utCFdsQhxM.jpg. It returns nil if there's no such image.
+type ImageOrigin interface {
+	FetchImage(ctx context.Context, path string) ([]byte, error)
+}
+
+// BlobCache keeps opaque bytes by key, evicting entries as it sees fit
+type BlobCache interface {
+	GetBlob(key string) ([]byte, bool, error) // false when missing
+	PutBlob(key string, data []byte) error
+}
+
+// Catalog sync: TMDB publishes a daily export listing every movie ID. A sync
+// stages the IDs, then fetches details for new and stale ones into Movie.
+const (
+	SyncIngesting = "ingesting" // reading the export; LinesRead is the resume point
+	SyncFetching  = "fetching"  // fetching details for staged IDs
+	SyncDone      = "done"
+)
+
+// CatalogSyncRun tracks one export file through the sync so an interrupted
+// run resumes where it stopped
+type CatalogSyncRun struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ExportFile string     `gorm:"size:255;index" json:"export_file"`
+	Status     string     `gorm:"size:20" json:"status"`
+	LinesRead  int        `json:"lines_read"`
+	Ingested   int        `json:"ingested"` // IDs staged from the export
+	Fetched    int        `json:"fetched"`  // movies upserted
+	Failed     int        `json:"failed"`
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// CatalogSyncItem is a staged movie ID from the export. FetchedAt is nil
+// until its details have been fetched; Error records the last failure.
+type CatalogSyncItem struct {
+	TMDBID        uint    `gorm:"primaryKey;autoIncrement:false"`
+	OriginalTitle string  `gorm:"size:300"`
+	Popularity    float64 `gorm:"index"`
+	FetchedAt     *time.Time
+	Error         string `gorm:"size:500"`
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// StringArray stores a []string in a Postgres text[] column. NULL elements
+// are read back as empty strings.
+type StringArray []string
+
+// Value encodes the array as a Postgres array literal, quoting every element
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, s := range a {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('"')
+		for _, r := range s {
+			if r == '"' || r == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// Scan decodes a one-dimensional Postgres array literal such as {a,"b c",NULL}
+func (a *StringArray) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into StringArray", src)
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return fmt.Errorf("invalid array literal %q", s)
+	}
+	s = s[1 : len(s)-1]
+
+	out := StringArray{}
+	for i := 0; i < len(s); {
+		var elem strings.Builder
+		if s[i] == '"' {
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				elem.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return errors.New("unterminated quoted array element")
+			}
+			i++ // closing quote
+			out = append(out, elem.String())
+		} else {
+			end := strings.IndexByte(s[i:], ',')
+			if end < 0 {
+				end = len(s) - i
+			}
+			if raw := s[i : i+end]; raw != "NULL" {
+				out = append(out, raw)
+			} else {
+				out = append(out, "")
+			}
+			i += end
+		}
+		if i < len(s) {
+			if s[i] != ',' {
+				return fmt.Errorf("invalid array literal %q", s)
+			}
+			i++
+		}
+	}
+	*a = out
+	return nil
+}
@@ -0,0 +1,207 @@
+package infra
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskCacheTempPrefix marks files still being written; leftovers from a
+// crash are removed on startup
+const diskCacheTempPrefix = "tmp-"
+
+// DiskCache implements domain.BlobCache with files in a directory, keeping
+// the total size under a limit by evicting the least recently used. Reads
+// touch a file's modification time, so the order survives restarts.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List               // front is most recently used
+	entries map[string]*list.Element // by file name
+}
+
+type diskCacheEntry struct {
+	name string
+	size int64
+}
+
+// NewDiskCache opens or creates the cache directory and indexes the files
+// already in it, evicting down to maxBytes
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if maxBytes <= 0 {
+		return nil, errors.New("disk cache size must be positive")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	dir = filepath.Clean(dir)
+	dc := &DiskCache{dir: dir, maxBytes: maxBytes, order: list.New(), entries: map[string]*list.Element{}}
+
+	type existing struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var files []existing
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if filepath.Dir(path) == dc.dir && strings.HasPrefix(d.Name(), diskCacheTempPrefix) {
+			return os.Remove(path)
+		}
+		if !isDiskCacheFile(path) {
+			return nil // not ours; never evict it
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, existing{name: d.Name(), size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		dc.entries[f.name] = dc.order.PushFront(&diskCacheEntry{name: f.name, size: f.size})
+		dc.size += f.size
+	}
+	dc.mu.Lock()
+	dc.evictLocked()
+	dc.mu.Unlock()
+	return dc, nil
+}
+
+// GetBlob reads a cached value and marks it recently used
+func (dc *DiskCache) GetBlob(key string) ([]byte, bool, error) {
+	name := diskCacheName(key)
+	dc.mu.Lock()
+	el, ok := dc.entries[name]
+	if ok {
+		dc.order.MoveToFront(el)
+	}
+	dc.mu.Unlock()
+	if !ok {
+		return nil, false, nil
+	}
+
+	path := dc.path(name)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Removed behind our back, or evicted since the lookup
+		dc.mu.Lock()
+		if el, ok := dc.entries[name]; ok {
+			dc.removeLocked(el)
+		}
+		dc.mu.Unlock()
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true, nil
+}
+
+// PutBlob stores a value, evicting old ones to make room. Values larger
+// than the whole cache aren't stored.
+func (dc *DiskCache) PutBlob(key string, data []byte) error {
+	size := int64(len(data))
+	if size > dc.maxBytes {
+		return nil
+	}
+	name := diskCacheName(key)
+	path := dc.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file and rename it into place, so readers never
+	// see a partial file
+	tmp, err := os.CreateTemp(dc.dir, diskCacheTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if el, ok := dc.entries[name]; ok {
+		entry := el.Value.(*diskCacheEntry)
+		dc.size += size - entry.size
+		entry.size = size
+		dc.order.MoveToFront(el)
+	} else {
+		dc.entries[name] = dc.order.PushFront(&diskCacheEntry{name: name, size: size})
+		dc.size += size
+	}
+	dc.evictLocked()
+	return nil
+}
+
+// evictLocked removes least recently used files until the cache fits
+func (dc *DiskCache) evictLocked() {
+	for dc.size > dc.maxBytes && dc.order.Len() > 0 {
+		el := dc.order.Back()
+		name := el.Value.(*diskCacheEntry).name
+		if err := os.Remove(dc.path(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("disk cache: evicting %s: %v", name, err)
+		}
+		dc.removeLocked(el)
+	}
+}
+
+func (dc *DiskCache) removeLocked(el *list.Element) {
+	entry := dc.order.Remove(el).(*diskCacheEntry)
+	delete(dc.entries, entry.name)
+	dc.size -= entry.size
+}
+
+// path spreads files over subdirectories by the first two characters of
+// their name, keeping directories small
+func (dc *DiskCache) path(name string) string {
+	return filepath.Join(dc.dir, name[:2], name)
+}
+
+// isDiskCacheFile reports whether path is where the cache would keep a file
+// of its name
+func isDiskCacheFile(path string) bool {
+	name := filepath.Base(path)
+	if len(name) != sha256.Size*2 || filepath.Base(filepath.Dir(path)) != name[:2] {
+		return false
+	}
+	_, err := hex.DecodeString(name)
+	return err == nil
+}
+
+// diskCacheName hashes a key into a file name, so any key is a safe one
+func diskCacheName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
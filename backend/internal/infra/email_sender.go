@@ -0,0 +1,74 @@
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+// SMTPNotificationSender emails notifications through an SMTP relay
+type SMTPNotificationSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPNotificationSenderFromEnv reads SMTP_HOST, SMTP_PORT (default 587),
+// SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM
+func NewSMTPNotificationSenderFromEnv() (*SMTPNotificationSender, error) {
+	host := os.Getenv("SMTP_HOST")
+	from := os.Getenv("SMTP_FROM")
+	if host == "" || from == "" {
+		return nil, errors.New("SMTP_HOST or SMTP_FROM not set")
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	var auth smtp.Auth
+	if user := os.Getenv("SMTP_USERNAME"); user != "" {
+		auth = smtp.PlainAuth("", user, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	return &SMTPNotificationSender{addr: host + ":" + port, auth: auth, from: from}, nil
+}
+
+func (s *SMTPNotificationSender) Send(to domain.ReminderCandidate, n domain.Notification) error {
+	if to.Email == "" {
+		return nil
+	}
+	// Titles come from TMDB; keep them from breaking out of the header, and
+	// encode them since headers must be ASCII
+	subject := mime.QEncoding.Encode("utf-8", strings.NewReplacer("\r", "", "\n", " ").Replace(n.Message))
+	body := fmt.Sprintf("Hi %s,\r\n\r\n%s\r\n\r\nIt's on your MovieMate watchlist.\r\n", to.Username, n.Message)
+	msg := strings.Join([]string{
+		"From: " + s.from,
+		"To: " + to.Email,
+		"Subject: " + subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{to.Email}, []byte(msg))
+}
+
+// LogNotificationSender logs notifications instead of sending them, for when
+// no mail server is configured
+type LogNotificationSender struct{}
+
+func NewLogNotificationSender() *LogNotificationSender {
+	return &LogNotificationSender{}
+}
+
+func (LogNotificationSender) Send(to domain.ReminderCandidate, n domain.Notification) error {
+	log.Printf("notification for user %d <%s>: %s", to.UserID, to.Email, n.Message)
+	return nil
+}
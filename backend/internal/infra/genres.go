@@ -0,0 +1,128 @@
+package infra
+
+import (
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultGenres is TMDB's movie and TV genre lists. They seed the Genre table
+// so it is usable before, or without, a refresh from TMDB.
+var defaultGenres = []domain.Genre{
+	{ID: 28, Name: "Action", Movie: true},
+	{ID: 12, Name: "Adventure", Movie: true},
+	{ID: 16, Name: "Animation", Movie: true, TV: true},
+	{ID: 35, Name: "Comedy", Movie: true, TV: true},
+	{ID: 80, Name: "Crime", Movie: true, TV: true},
+	{ID: 99, Name: "Documentary", Movie: true, TV: true},
+	{ID: 18, Name: "Drama", Movie: true, TV: true},
+	{ID: 10751, Name: "Family", Movie: true, TV: true},
+	{ID: 14, Name: "Fantasy", Movie: true},
+	{ID: 36, Name: "History", Movie: true},
+	{ID: 27, Name: "Horror", Movie: true},
+	{ID: 10402, Name: "Music", Movie: true},
+	{ID: 9648, Name: "Mystery", Movie: true, TV: true},
+	{ID: 10749, Name: "Romance", Movie: true},
+	{ID: 878, Name: "Science Fiction", Movie: true},
+	{ID: 10770, Name: "TV Movie", Movie: true},
+	{ID: 53, Name: "Thriller", Movie: true},
+	{ID: 10752, Name: "War", Movie: true},
+	{ID: 37, Name: "Western", Movie: true, TV: true},
+	{ID: 10759, Name: "Action & Adventure", TV: true},
+	{ID: 10762, Name: "Kids", TV: true},
+	{ID: 10763, Name: "News", TV: true},
+	{ID: 10764, Name: "Reality", TV: true},
+	{ID: 10765, Name: "Sci-Fi & Fantasy", TV: true},
+	{ID: 10766, Name: "Soap", TV: true},
+	{ID: 10767, Name: "Talk", TV: true},
+	{ID: 10768, Name: "War & Politics", TV: true},
+}
+
+// seedGenres adds any default genre that is missing, leaving refreshed ones alone
+func seedGenres(db *gorm.DB) error {
+	genres := append([]domain.Genre(nil), defaultGenres...)
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&genres).Error
+}
+
+// localGenreIDBase starts the IDs of genres made up by the CSV migration,
+// well clear of TMDB's genre IDs
+const localGenreIDBase = 1_000_000
+
+// maxGenreName is the size of Genre.Name
+const maxGenreName = 50
+
+// migrateGenreCSV moves the comma-separated movies.genres column that predates
+// the Genre table into movie_genres, then drops it. Names that aren't a known
+// genre get a Genre row of their own, on neither TMDB list. If a name can't be
+// stored the column is kept, so no genre is lost.
+func migrateGenreCSV(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("movies", "genres") {
+		return nil
+	}
+	var genres []domain.Genre
+	if err := db.Find(&genres).Error; err != nil {
+		return err
+	}
+	byName := make(map[string]uint, len(genres))
+	nextID := uint(localGenreIDBase)
+	for _, g := range genres {
+		byName[strings.ToLower(g.Name)] = g.ID
+		nextID = max(nextID, g.ID+1)
+	}
+
+	var rows []struct {
+		ID     uint
+		Genres string
+	}
+	if err := db.Table("movies").Select("id, genres").Where("genres <> ''").Scan(&rows).Error; err != nil {
+		return err
+	}
+	var links []domain.MovieGenre
+	var added []domain.Genre
+	var tooLong []string
+	for _, row := range rows {
+		for _, name := range strings.Split(row.Genres, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			id, ok := byName[strings.ToLower(name)]
+			if !ok {
+				if utf8.RuneCountInString(name) > maxGenreName {
+					tooLong = append(tooLong, name)
+					continue
+				}
+				id = nextID
+				nextID++
+				byName[strings.ToLower(name)] = id
+				added = append(added, domain.Genre{ID: id, Name: name})
+			}
+			links = append(links, domain.MovieGenre{MovieID: row.ID, GenreID: id})
+		}
+	}
+	if len(added) > 0 {
+		log.Printf("genre migration: adding %d genres TMDB doesn't list", len(added))
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if len(added) > 0 {
+			if err := tx.CreateInBatches(added, 500).Error; err != nil {
+				return err
+			}
+		}
+		if len(links) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(links, 500).Error; err != nil {
+				return err
+			}
+		}
+		if len(tooLong) > 0 {
+			log.Printf("genre migration: keeping movies.genres, names longer than %d characters: %q", maxGenreName, tooLong)
+			return nil
+		}
+		return tx.Exec("ALTER TABLE movies DROP COLUMN genres").Error
+	})
+}
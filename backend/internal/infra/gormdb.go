@@ -27,8 +27,43 @@ func NewDB() (*gorm.DB, error) {
 		sqlDB.SetMaxOpenConns(10)
 		sqlDB.SetConnMaxLifetime(30 * time.Minute)
 	}
+	// Movie.Genres uses MovieGenre as its join table
+	if err := db.SetupJoinTable(&domain.Movie{}, "Genres", &domain.MovieGenre{}); err != nil {
+		return nil, err
+	}
 	// minimal migrations
-	if err := db.AutoMigrate(&domain.User{}, &domain.Movie{}, &domain.WatchlistItem{}); err != nil {
+	if err := db.AutoMigrate(&domain.User{}, &domain.Movie{}, &domain.WatchlistItem{}, &domain.WatchlistTag{}, &domain.MovieMetadata{}, &domain.DiaryEntry{},
+		&domain.Watchlist{}, &domain.PersonalListSharing{}, &domain.WatchlistMember{}, &domain.WatchlistInvite{}, &domain.WatchlistChange{}, &domain.WatchlistVersion{},
+		&domain.ReleaseDate{}, &domain.Notification{}, &domain.WatchOffer{}, &domain.WatchOffersFetch{}, &domain.UserService{},
+		&domain.TVShow{}, &domain.TVSeason{}, &domain.TVEpisode{}, &domain.EpisodeProgress{}, &domain.CacheEntry{},
+		&domain.CatalogSyncRun{}, &domain.CatalogSyncItem{}, &domain.Genre{}, &domain.MovieGenre{}, &domain.MovieAudit{},
+		&domain.Person{}, &domain.Credit{}); err != nil {
+		return nil, err
+	}
+	// Items were unique per user before shared lists; the index now includes list_id
+	if db.Migrator().HasIndex(&domain.WatchlistItem{}, "idx_user_movie") {
+		if err := db.Migrator().DropIndex(&domain.WatchlistItem{}, "idx_user_movie"); err != nil {
+			return nil, err
+		}
+	}
+	// Movie titles were unique before external IDs; remakes share titles
+	if db.Migrator().HasIndex(&domain.Movie{}, "idx_movies_title") {
+		if err := db.Migrator().DropIndex(&domain.Movie{}, "idx_movies_title"); err != nil {
+			return nil, err
+		}
+	}
+	// Title lookups are case-insensitive and usually narrowed by year
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_movies_title_year ON movies (LOWER(title), year)").Error; err != nil {
+		return nil, err
+	}
+	// Genres were a CSV column before the Genre table
+	if err := seedGenres(db); err != nil {
+		return nil, err
+	}
+	if err := migrateGenreCSV(db); err != nil {
+		return nil, err
+	}
+	if err := migrateMovieSearch(db); err != nil {
 		return nil, err
 	}
 	return db, nil
@@ -0,0 +1,110 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+// tmdbImageBaseURL serves TMDB images at full size; no credential is needed
+const tmdbImageBaseURL = "https://image.tmdb.org/t/p/original"
+
+// maxOriginImageBytes bounds a source image download. TMDB originals are a
+// few MB at most.
+const maxOriginImageBytes = 20 << 20
+
+// NewImageOriginFromEnv returns the source for the image proxy:
+// IMAGE_ORIGIN_DIR serves files from a local directory (for development
+// without network access), otherwise images come from IMAGE_ORIGIN_URL,
+// TMDB's full-size images by default
+func NewImageOriginFromEnv() domain.ImageOrigin {
+	if dir := os.Getenv("IMAGE_ORIGIN_DIR"); dir != "" {
+		return NewDirImageOrigin(dir)
+	}
+	baseURL := os.Getenv("IMAGE_ORIGIN_URL")
+	if baseURL == "" {
+		baseURL = tmdbImageBaseURL
+	}
+	return NewHTTPImageOrigin(baseURL)
+}
+
+// HTTPImageOrigin fetches images from a base URL such as TMDB's image server
+type HTTPImageOrigin struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPImageOrigin(baseURL string) *HTTPImageOrigin {
+	return &HTTPImageOrigin{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// FetchImage downloads baseURL+path
+func (o *HTTPImageOrigin) FetchImage(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("image origin returned %d for %s", resp.StatusCode, path)
+	}
+	return readLimited(resp.Body, path)
+}
+
+// DirImageOrigin serves images from a local directory, laid out like TMDB's
+// file paths
+type DirImageOrigin struct {
+	dir string
+}
+
+func NewDirImageOrigin(dir string) *DirImageOrigin {
+	return &DirImageOrigin{dir: dir}
+}
+
+// FetchImage reads path below the directory; it can't escape it
+func (o *DirImageOrigin) FetchImage(ctx context.Context, path string) ([]byte, error) {
+	root, err := os.OpenRoot(o.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+	f, err := root.Open(filepath.FromSlash(strings.TrimPrefix(path, "/")))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readLimited(f, path)
+}
+
+func readLimited(r io.Reader, path string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxOriginImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxOriginImageBytes {
+		return nil, fmt.Errorf("image %s is larger than %d bytes", path, maxOriginImageBytes)
+	}
+	return data, nil
+}
@@ -0,0 +1,45 @@
+package infra
+
+import "gorm.io/gorm"
+
+// Full-text search over movies: a weighted tsvector kept up to date by a
+// trigger, since array_to_string isn't immutable enough for a generated
+// column. Titles weigh most, then cast, then the overview. The text search
+// configuration must match the one queries use (repository.searchConfig).
+var movieSearchMigrations = []string{
+	`ALTER TABLE movies ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+	`CREATE OR REPLACE FUNCTION movies_search_vector_update() RETURNS trigger AS $$
+BEGIN
+	NEW.search_vector :=
+		setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(NEW.original_title, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(array_to_string(NEW.cast_names, ' '), '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(NEW.description, '')), 'C');
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS movies_search_vector ON movies`,
+	`CREATE TRIGGER movies_search_vector BEFORE INSERT OR UPDATE OF title, original_title, cast_names, description
+	ON movies FOR EACH ROW EXECUTE FUNCTION movies_search_vector_update()`,
+	`CREATE INDEX IF NOT EXISTS idx_movies_search ON movies USING GIN (search_vector)`,
+	// Rows from before the trigger existed; touching title fires it
+	`UPDATE movies SET title = title WHERE search_vector IS NULL`,
+
+	// Trigram indexes behind typo-tolerant search and autocomplete
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS idx_movies_title_trgm ON movies USING GIN (LOWER(title) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_movies_original_title_trgm ON movies USING GIN (LOWER(original_title) gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_people_name_trgm ON people USING GIN (LOWER(name) gin_trgm_ops)`,
+	// People autocomplete used to search cast names; it reads people now
+	`DROP INDEX IF EXISTS idx_movies_cast_trgm`,
+	`DROP FUNCTION IF EXISTS movies_cast_text(text[])`,
+}
+
+func migrateMovieSearch(db *gorm.DB) error {
+	for _, stmt := range movieSearchMigrations {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
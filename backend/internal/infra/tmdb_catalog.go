@@ -0,0 +1,219 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+// tmdbSource fetches a TMDB path; TMDBClient is the real one
+type tmdbSource interface {
+	get(ctx context.Context, path string, params url.Values, out any) (bool, error)
+}
+
+// CatalogService implements domain.CatalogProvider on top of a tmdbSource
+type CatalogService struct {
+	source tmdbSource
+}
+
+func NewTMDBCatalogService(client *TMDBClient) *CatalogService {
+	return &CatalogService{source: client}
+}
+
+// Trending returns /trending/{all,movie,tv}/{day,week}
+func (s *CatalogService) Trending(mediaType, window string, page int) (*domain.CatalogPage, error) {
+	return s.page(fmt.Sprintf("/trending/%s/%s", mediaType, window), pageParams(page), mediaType)
+}
+
+// List returns a curated list, e.g. /movie/top_rated or /tv/on_the_air
+func (s *CatalogService) List(mediaType, list string, page int) (*domain.CatalogPage, error) {
+	return s.page(fmt.Sprintf("/%s/%s", mediaType, list), pageParams(page), mediaType)
+}
+
+// Discover returns /discover/{movie,tv} with the query's filters
+func (s *CatalogService) Discover(q domain.DiscoverQuery) (*domain.CatalogPage, error) {
+	params := pageParams(q.Page)
+	params.Set("include_adult", "false")
+	if q.SortBy != "" {
+		params.Set("sort_by", q.SortBy)
+	}
+	if len(q.Genres) > 0 {
+		ids := make([]string, len(q.Genres))
+		for i, g := range q.Genres {
+			ids[i] = strconv.Itoa(g)
+		}
+		params.Set("with_genres", strings.Join(ids, ","))
+	}
+	// Movies filter on release date, shows on first air date
+	dateField, yearField := "primary_release_date", "primary_release_year"
+	if q.MediaType == "tv" {
+		dateField, yearField = "first_air_date", "first_air_date_year"
+	}
+	if q.Year > 0 {
+		params.Set(yearField, strconv.Itoa(q.Year))
+	}
+	if q.ReleasedFrom != "" {
+		params.Set(dateField+".gte", q.ReleasedFrom)
+	}
+	if q.ReleasedTo != "" {
+		params.Set(dateField+".lte", q.ReleasedTo)
+	}
+	return s.page("/discover/"+q.MediaType, params, q.MediaType)
+}
+
+// Search returns /search/multi: movies, shows and people
+func (s *CatalogService) Search(query string, page int) (*domain.CatalogPage, error) {
+	params := pageParams(page)
+	params.Set("query", query)
+	params.Set("include_adult", "false")
+	return s.page("/search/multi", params, "")
+}
+
+// Details returns /{movie,tv}/{id} with credits and videos appended
+func (s *CatalogService) Details(mediaType string, id uint) (*domain.CatalogDetails, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var d struct {
+		domain.CatalogDetails
+		ExternalIDs struct {
+			IMDbID string `json:"imdb_id"`
+		} `json:"external_ids"`
+	}
+	params := url.Values{"append_to_response": {"credits,videos,external_ids"}}
+	found, err := s.source.get(ctx, fmt.Sprintf("/%s/%d", mediaType, id), params, &d)
+	if err != nil || !found {
+		return nil, err
+	}
+	d.MediaType = mediaType
+	if d.IMDbID == "" {
+		d.IMDbID = d.ExternalIDs.IMDbID
+	}
+	return &d.CatalogDetails, nil
+}
+
+// Videos returns /{movie,tv}/{id}/videos
+func (s *CatalogService) Videos(mediaType string, id uint) (*domain.CatalogVideos, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var v domain.CatalogVideos
+	found, err := s.source.get(ctx, fmt.Sprintf("/%s/%d/videos", mediaType, id), nil, &v)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Images returns /{movie,tv}/{id}/images in English or without text
+func (s *CatalogService) Images(mediaType string, id uint) (*domain.CatalogImages, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var img domain.CatalogImages
+	params := url.Values{"include_image_language": {"en,null"}}
+	found, err := s.source.get(ctx, fmt.Sprintf("/%s/%d/images", mediaType, id), params, &img)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &img, nil
+}
+
+// Genres returns /genre/{movie,tv}/list
+func (s *CatalogService) Genres(mediaType string) ([]domain.CatalogGenre, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var res struct {
+		Genres []domain.CatalogGenre `json:"genres"`
+	}
+	path := fmt.Sprintf("/genre/%s/list", mediaType)
+	found, err := s.source.get(ctx, path, nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("tmdb genre list %s not found", path)
+	}
+	return res.Genres, nil
+}
+
+// page fetches a listing. mediaType is stamped on results that lack one;
+// mixed listings pass "all" or "" and keep TMDB's own.
+func (s *CatalogService) page(path string, params url.Values, mediaType string) (*domain.CatalogPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var p domain.CatalogPage
+	found, err := s.source.get(ctx, path, params, &p)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("tmdb listing %s not found", path)
+	}
+	if p.Results == nil {
+		p.Results = []domain.CatalogItem{}
+	}
+	if mediaType == "movie" || mediaType == "tv" {
+		for i := range p.Results {
+			if p.Results[i].MediaType == "" {
+				p.Results[i].MediaType = mediaType
+			}
+		}
+	}
+	return &p, nil
+}
+
+func pageParams(page int) url.Values {
+	if page <= 0 {
+		page = 1
+	}
+	return url.Values{"page": {strconv.Itoa(page)}}
+}
+
+// fixtureSource answers TMDB paths from saved responses, ignoring query
+// parameters. Unknown paths are treated as 404s.
+type fixtureSource struct {
+	responses map[string]json.RawMessage
+}
+
+func (f *fixtureSource) get(ctx context.Context, path string, params url.Values, out any) (bool, error) {
+	raw, ok := f.responses[path]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, out)
+}
+
+// NewFixtureCatalogService serves the catalog from a JSON file mapping TMDB
+// paths to responses, e.g. {"/movie/27205": {...}}; see testdata/catalog.json.
+// It stands in for TMDB in local development and tests.
+func NewFixtureCatalogService(path string) (*CatalogService, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var responses map[string]json.RawMessage
+	if err := json.Unmarshal(data, &responses); err != nil {
+		return nil, fmt.Errorf("catalog fixture %s: %w", path, err)
+	}
+	return &CatalogService{source: &fixtureSource{responses: responses}}, nil
+}
+
+// NewFixtureCatalogServiceFromEnv loads the file named by TMDB_CATALOG_FIXTURE
+func NewFixtureCatalogServiceFromEnv() (*CatalogService, error) {
+	path := os.Getenv("TMDB_CATALOG_FIXTURE")
+	if path == "" {
+		return nil, errors.New("TMDB_CATALOG_FIXTURE not set")
+	}
+	return NewFixtureCatalogService(path)
+}
@@ -0,0 +1,191 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const tmdbBaseURL = "https://api.themoviedb.org/3"
+
+// TMDB allows roughly 50 requests per second per IP; stay a little under it
+const defaultTMDBRateLimit = 40
+
+// Retry policy for rate-limited (429) and failed (5xx, network) requests
+const (
+	tmdbMaxRetries   = 2
+	tmdbRetryBackoff = 500 * time.Millisecond
+	tmdbMaxRetryWait = 10 * time.Second
+)
+
+// TMDBClient is the one place the TMDB credential lives. Every request times
+// out, is retried with backoff when TMDB is rate limiting or failing, and is
+// paced so the whole process stays under TMDB's rate limit.
+type TMDBClient struct {
+	apiKey      string
+	accessToken string
+	client      *http.Client
+	limiter     *tmdbRateLimiter
+}
+
+// NewTMDBClientFromEnv reads TMDB_API_KEY or TMDB_READ_ACCESS_TOKEN, and
+// TMDB_RATE_LIMIT (requests per second)
+func NewTMDBClientFromEnv() (*TMDBClient, error) {
+	key := os.Getenv("TMDB_API_KEY")
+	token := os.Getenv("TMDB_READ_ACCESS_TOKEN")
+	if key == "" && token == "" {
+		return nil, errors.New("TMDB_API_KEY or TMDB_READ_ACCESS_TOKEN not set")
+	}
+	rate, err := strconv.Atoi(os.Getenv("TMDB_RATE_LIMIT"))
+	if err != nil || rate <= 0 {
+		rate = defaultTMDBRateLimit
+	}
+	return &TMDBClient{
+		apiKey:      key,
+		accessToken: token,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		limiter:     &tmdbRateLimiter{interval: time.Second / time.Duration(rate)},
+	}, nil
+}
+
+// tmdbStatusError is a non-2xx response other than 404
+type tmdbStatusError struct {
+	status     int
+	path       string
+	retryAfter time.Duration
+}
+
+func (e *tmdbStatusError) Error() string {
+	return fmt.Sprintf("tmdb error status=%d path=%s", e.status, e.path)
+}
+
+func (e *tmdbStatusError) retryable() bool {
+	return e.status == http.StatusTooManyRequests || e.status >= 500
+}
+
+// get performs an authenticated GET and decodes the body into out.
+// A 404 is reported as found=false rather than an error.
+func (c *TMDBClient) get(ctx context.Context, path string, params url.Values, out any) (bool, error) {
+	for attempt := 0; ; attempt++ {
+		found, err := c.do(ctx, path, params, out)
+		if err == nil || attempt >= tmdbMaxRetries || ctx.Err() != nil {
+			return found, err
+		}
+		wait := tmdbRetryBackoff << attempt
+		var statusErr *tmdbStatusError
+		if errors.As(err, &statusErr) {
+			if !statusErr.retryable() {
+				return false, err
+			}
+			if statusErr.retryAfter > 0 {
+				wait = min(statusErr.retryAfter, tmdbMaxRetryWait)
+			}
+			if statusErr.status == http.StatusTooManyRequests {
+				// Hold back every caller, not just this one
+				c.limiter.pause(wait)
+			}
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return false, err
+		}
+	}
+}
+
+// do makes a single attempt
+func (c *TMDBClient) do(ctx context.Context, path string, params url.Values, out any) (bool, error) {
+	if err := c.limiter.wait(ctx); err != nil {
+		return false, err
+	}
+
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+	if c.accessToken == "" {
+		query.Set("api_key", c.apiKey)
+	}
+	endpoint := tmdbBaseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		statusErr := &tmdbStatusError{status: resp.StatusCode, path: path}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			statusErr.retryAfter = time.Duration(secs) * time.Second
+		}
+		return false, statusErr
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// tmdbRateLimiter spaces requests at least interval apart across goroutines
+type tmdbRateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the caller's slot comes up
+func (l *tmdbRateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	slot := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleepContext(ctx, time.Until(slot))
+}
+
+// pause pushes every later slot back by d, e.g. after TMDB answers 429
+func (l *tmdbRateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); l.next.Before(until) {
+		l.next = until
+	}
+}
+
+// sleepContext waits for d, returning early with the context's error
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
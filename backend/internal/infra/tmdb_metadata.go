@@ -0,0 +1,234 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+// TMDBMetadataService implements domain.MetadataProvider against the TMDB v3 API
+type TMDBMetadataService struct {
+	client *TMDBClient
+}
+
+func NewTMDBMetadataService(client *TMDBClient) *TMDBMetadataService {
+	return &TMDBMetadataService{client: client}
+}
+
+type tmdbDetails struct {
+	ID             uint    `json:"id"`
+	IMDbID         string  `json:"imdb_id"`
+	Title          string  `json:"title"`
+	Name           string  `json:"name"`
+	PosterPath     string  `json:"poster_path"`
+	ReleaseDate    string  `json:"release_date"`
+	FirstAirDate   string  `json:"first_air_date"`
+	Runtime        int     `json:"runtime"`
+	EpisodeRunTime []int   `json:"episode_run_time"`
+	VoteAverage    float64 `json:"vote_average"`
+	Genres         []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	ExternalIDs struct {
+		IMDbID string `json:"imdb_id"`
+	} `json:"external_ids"`
+}
+
+// tmdbSearchResult is a movie or TV entry from /search and /find
+type tmdbSearchResult struct {
+	ID           uint    `json:"id"`
+	Title        string  `json:"title"`
+	Name         string  `json:"name"`
+	PosterPath   string  `json:"poster_path"`
+	ReleaseDate  string  `json:"release_date"`
+	FirstAirDate string  `json:"first_air_date"`
+	VoteAverage  float64 `json:"vote_average"`
+}
+
+// toMetadata converts a search hit. Runtime and genres aren't part of search
+// results, so FetchedAt is left zero to make the cache refresh it on first use.
+func (r tmdbSearchResult) toMetadata(mediaType string) domain.MovieMetadata {
+	m := domain.MovieMetadata{
+		TMDBID:      r.ID,
+		MediaType:   mediaType,
+		Title:       r.Title,
+		PosterPath:  r.PosterPath,
+		Rating:      r.VoteAverage,
+		ReleaseYear: yearOf(r.ReleaseDate),
+	}
+	if mediaType == "tv" {
+		m.Title = r.Name
+		m.ReleaseYear = yearOf(r.FirstAirDate)
+	}
+	return m
+}
+
+// FetchMetadata resolves a bare TMDB ID. Watchlist items don't record the media
+// type, so movies are tried first and TV shows second, like the frontend did.
+func (s *TMDBMetadataService) FetchMetadata(tmdbID uint) (*domain.MovieMetadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	for _, mediaType := range []string{"movie", "tv"} {
+		var d tmdbDetails
+		found, err := s.get(ctx, fmt.Sprintf("/%s/%d", mediaType, tmdbID), url.Values{"append_to_response": {"external_ids"}}, &d)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		m := &domain.MovieMetadata{
+			TMDBID:     tmdbID,
+			MediaType:  mediaType,
+			IMDbID:     d.IMDbID,
+			Title:      d.Title,
+			PosterPath: d.PosterPath,
+			Runtime:    d.Runtime,
+			Rating:     d.VoteAverage,
+			FetchedAt:  time.Now(),
+		}
+		m.ReleaseYear = yearOf(d.ReleaseDate)
+		if mediaType == "tv" {
+			m.Title = d.Name
+			m.IMDbID = d.ExternalIDs.IMDbID
+			m.ReleaseYear = yearOf(d.FirstAirDate)
+			if len(d.EpisodeRunTime) > 0 {
+				m.Runtime = d.EpisodeRunTime[0]
+			}
+		}
+		names := make([]string, 0, len(d.Genres))
+		for _, g := range d.Genres {
+			names = append(names, g.Name)
+		}
+		m.Genres = strings.Join(names, ",")
+		return m, nil
+	}
+	return nil, nil
+}
+
+// FindByIMDbID resolves an IMDb ID (e.g. "tt0816692") through /find
+func (s *TMDBMetadataService) FindByIMDbID(imdbID string) (*domain.MovieMetadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var res struct {
+		MovieResults []tmdbSearchResult `json:"movie_results"`
+		TVResults    []tmdbSearchResult `json:"tv_results"`
+	}
+	found, err := s.get(ctx, "/find/"+url.PathEscape(imdbID), url.Values{"external_source": {"imdb_id"}}, &res)
+	if err != nil || !found {
+		return nil, err
+	}
+	var m domain.MovieMetadata
+	switch {
+	case len(res.MovieResults) > 0:
+		m = res.MovieResults[0].toMetadata("movie")
+	case len(res.TVResults) > 0:
+		m = res.TVResults[0].toMetadata("tv")
+	default:
+		return nil, nil
+	}
+	m.IMDbID = imdbID
+	return &m, nil
+}
+
+// SearchTitle returns the first page of /search/movie or /search/tv results
+func (s *TMDBMetadataService) SearchTitle(mediaType, title string, year int) ([]domain.MovieMetadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	params := url.Values{"query": {title}, "include_adult": {"false"}}
+	if year > 0 {
+		if mediaType == "tv" {
+			params.Set("first_air_date_year", strconv.Itoa(year))
+		} else {
+			params.Set("year", strconv.Itoa(year))
+		}
+	}
+	var res struct {
+		Results []tmdbSearchResult `json:"results"`
+	}
+	if _, err := s.get(ctx, "/search/"+mediaType, params, &res); err != nil {
+		return nil, err
+	}
+	out := make([]domain.MovieMetadata, 0, len(res.Results))
+	for _, r := range res.Results {
+		out = append(out, r.toMetadata(mediaType))
+	}
+	return out, nil
+}
+
+// TMDB release types that count as a film's release; the others are
+// premiere (1), digital (4), physical (5) and TV (6)
+const (
+	tmdbReleaseLimited    = 2
+	tmdbReleaseTheatrical = 3
+)
+
+// FetchReleaseDates returns one date per region: the earliest theatrical
+// release, falling back to the earliest release of any kind (e.g. digital)
+func (s *TMDBMetadataService) FetchReleaseDates(tmdbID uint) ([]domain.ReleaseDate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var res struct {
+		Results []struct {
+			Region       string `json:"iso_3166_1"`
+			ReleaseDates []struct {
+				ReleaseDate time.Time `json:"release_date"`
+				Type        int       `json:"type"`
+			} `json:"release_dates"`
+		} `json:"results"`
+	}
+	found, err := s.get(ctx, fmt.Sprintf("/movie/%d/release_dates", tmdbID), nil, &res)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	now := time.Now()
+	dates := make([]domain.ReleaseDate, 0, len(res.Results))
+	for _, r := range res.Results {
+		var theatrical, earliest time.Time
+		for _, d := range r.ReleaseDates {
+			if d.ReleaseDate.IsZero() {
+				continue
+			}
+			if earliest.IsZero() || d.ReleaseDate.Before(earliest) {
+				earliest = d.ReleaseDate
+			}
+			if (d.Type == tmdbReleaseLimited || d.Type == tmdbReleaseTheatrical) &&
+				(theatrical.IsZero() || d.ReleaseDate.Before(theatrical)) {
+				theatrical = d.ReleaseDate
+			}
+		}
+		date := theatrical
+		if date.IsZero() {
+			date = earliest
+		}
+		if date.IsZero() || len(r.Region) != 2 {
+			continue
+		}
+		dates = append(dates, domain.ReleaseDate{TMDBID: tmdbID, Region: r.Region, Date: date, FetchedAt: now})
+	}
+	return dates, nil
+}
+
+// yearOf extracts the year from a TMDB "YYYY-MM-DD" date
+func yearOf(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	y, _ := strconv.Atoi(date[:4])
+	return y
+}
+
+// get fetches a TMDB path through the shared client; see TMDBClient.get
+func (s *TMDBMetadataService) get(ctx context.Context, path string, params url.Values, out any) (bool, error) {
+	return s.client.get(ctx, path, params, out)
+}
@@ -0,0 +1,87 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+// FetchShow returns a show and its season list from /tv/{id}
+func (s *TMDBMetadataService) FetchShow(showID uint) (*domain.TVShow, []domain.TVSeason, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var res struct {
+		Name       string `json:"name"`
+		PosterPath string `json:"poster_path"`
+		Status     string `json:"status"`
+		Seasons    []struct {
+			SeasonNumber int    `json:"season_number"`
+			Name         string `json:"name"`
+			EpisodeCount int    `json:"episode_count"`
+			AirDate      string `json:"air_date"`
+		} `json:"seasons"`
+	}
+	found, err := s.get(ctx, fmt.Sprintf("/tv/%d", showID), nil, &res)
+	if err != nil || !found {
+		return nil, nil, err
+	}
+
+	show := &domain.TVShow{TMDBID: showID, Name: res.Name, PosterPath: res.PosterPath, Status: res.Status, FetchedAt: time.Now()}
+	seasons := make([]domain.TVSeason, 0, len(res.Seasons))
+	for _, se := range res.Seasons {
+		seasons = append(seasons, domain.TVSeason{
+			ShowID:       showID,
+			SeasonNumber: se.SeasonNumber,
+			Name:         se.Name,
+			EpisodeCount: se.EpisodeCount,
+			AirDate:      parseTMDBDate(se.AirDate),
+		})
+	}
+	return show, seasons, nil
+}
+
+// FetchSeason returns a season's episodes from /tv/{id}/season/{n}
+func (s *TMDBMetadataService) FetchSeason(showID uint, season int) ([]domain.TVEpisode, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var res struct {
+		Episodes []struct {
+			EpisodeNumber int    `json:"episode_number"`
+			Name          string `json:"name"`
+			AirDate       string `json:"air_date"`
+			Runtime       int    `json:"runtime"`
+			StillPath     string `json:"still_path"`
+		} `json:"episodes"`
+	}
+	found, err := s.get(ctx, fmt.Sprintf("/tv/%d/season/%d", showID, season), nil, &res)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	episodes := make([]domain.TVEpisode, 0, len(res.Episodes))
+	for _, e := range res.Episodes {
+		episodes = append(episodes, domain.TVEpisode{
+			ShowID:        showID,
+			SeasonNumber:  season,
+			EpisodeNumber: e.EpisodeNumber,
+			Name:          e.Name,
+			AirDate:       parseTMDBDate(e.AirDate),
+			Runtime:       e.Runtime,
+			StillPath:     e.StillPath,
+		})
+	}
+	return episodes, nil
+}
+
+// parseTMDBDate parses a "YYYY-MM-DD" date; TMDB leaves unknown dates empty
+func parseTMDBDate(date string) *time.Time {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
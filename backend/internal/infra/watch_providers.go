@@ -0,0 +1,117 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+// tmdbWatchProviders is the body of /{movie,tv}/{id}/watch/providers
+type tmdbWatchProviders struct {
+	Results map[string]map[string]json.RawMessage `json:"results"` // region -> offer type -> providers (plus "link")
+}
+
+type tmdbProvider struct {
+	ProviderID      int    `json:"provider_id"`
+	ProviderName    string `json:"provider_name"`
+	LogoPath        string `json:"logo_path"`
+	DisplayPriority int    `json:"display_priority"`
+}
+
+// tmdbOfferTypes are the keys of a region entry that list providers
+var tmdbOfferTypes = []string{domain.OfferStream, domain.OfferFree, domain.OfferAds, domain.OfferRent, domain.OfferBuy}
+
+func (w tmdbWatchProviders) toOffers(tmdbID uint) ([]domain.WatchOffer, error) {
+	var offers []domain.WatchOffer
+	for region, entry := range w.Results {
+		if len(region) != 2 {
+			continue
+		}
+		for _, typ := range tmdbOfferTypes {
+			raw, ok := entry[typ]
+			if !ok {
+				continue
+			}
+			var providers []tmdbProvider
+			if err := json.Unmarshal(raw, &providers); err != nil {
+				return nil, err
+			}
+			for _, p := range providers {
+				offers = append(offers, domain.WatchOffer{
+					TMDBID:       tmdbID,
+					Region:       region,
+					ProviderID:   p.ProviderID,
+					Type:         typ,
+					ProviderName: p.ProviderName,
+					LogoPath:     p.LogoPath,
+					Priority:     p.DisplayPriority,
+				})
+			}
+		}
+	}
+	return offers, nil
+}
+
+// FetchWatchOffers returns every region's offers from TMDB (data by JustWatch)
+func (s *TMDBMetadataService) FetchWatchOffers(tmdbID uint, mediaType string) ([]domain.WatchOffer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var res tmdbWatchProviders
+	found, err := s.get(ctx, fmt.Sprintf("/%s/%d/watch/providers", mediaType, tmdbID), nil, &res)
+	if err != nil || !found {
+		return nil, err
+	}
+	return res.toOffers(tmdbID)
+}
+
+// FixtureWatchProviderService serves watch offers from a JSON file instead of
+// TMDB. The file maps TMDB IDs to watch/providers responses, e.g.
+// {"27205": {"results": {"US": {"flatrate": [...]}}}}; see testdata/watch_providers.json.
+type FixtureWatchProviderService struct {
+	titles map[uint]tmdbWatchProviders
+}
+
+func NewFixtureWatchProviderService(path string) (*FixtureWatchProviderService, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]tmdbWatchProviders
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("watch providers fixture %s: %w", path, err)
+	}
+	titles := make(map[uint]tmdbWatchProviders, len(raw))
+	for key, w := range raw {
+		id, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("watch providers fixture %s: bad TMDB ID %q", path, key)
+		}
+		titles[uint(id)] = w
+	}
+	return &FixtureWatchProviderService{titles: titles}, nil
+}
+
+// NewFixtureWatchProviderServiceFromEnv loads the file named by WATCH_PROVIDERS_FIXTURE
+func NewFixtureWatchProviderServiceFromEnv() (*FixtureWatchProviderService, error) {
+	path := os.Getenv("WATCH_PROVIDERS_FIXTURE")
+	if path == "" {
+		return nil, errors.New("WATCH_PROVIDERS_FIXTURE not set")
+	}
+	return NewFixtureWatchProviderService(path)
+}
+
+// FetchWatchOffers returns the fixture's offers; unknown titles have none
+func (s *FixtureWatchProviderService) FetchWatchOffers(tmdbID uint, mediaType string) ([]domain.WatchOffer, error) {
+	w, ok := s.titles[tmdbID]
+	if !ok {
+		return nil, nil
+	}
+	return w.toOffers(tmdbID)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Streaming availability
+
+// GetWatchOffers returns offers for the titles in one region, best placed providers first
+func (r *GormRepo) GetWatchOffers(ids []uint, region string) ([]domain.WatchOffer, error) {
+	var offers []domain.WatchOffer
+	if len(ids) == 0 {
+		return offers, nil
+	}
+	if err := r.db.Where("tmdb_id IN ? AND region = ?", ids, region).
+		Order("priority, provider_name").
+		Find(&offers).Error; err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+// GetWatchOffersFetchedAt returns when each title's offers were last fetched;
+// titles never fetched are omitted
+func (r *GormRepo) GetWatchOffersFetchedAt(ids []uint) (map[uint]time.Time, error) {
+	fetched := make(map[uint]time.Time, len(ids))
+	if len(ids) == 0 {
+		return fetched, nil
+	}
+	var rows []domain.WatchOffersFetch
+	if err := r.db.Where("tmdb_id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		fetched[row.TMDBID] = row.FetchedAt
+	}
+	return fetched, nil
+}
+
+// ReplaceWatchOffers stores a title's offers in every region and marks it fetched
+func (r *GormRepo) ReplaceWatchOffers(tmdbID uint, offers []domain.WatchOffer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tmdb_id = ?", tmdbID).Delete(&domain.WatchOffer{}).Error; err != nil {
+			return err
+		}
+		if len(offers) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&offers, 200).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).
+			Create(&domain.WatchOffersFetch{TMDBID: tmdbID, FetchedAt: time.Now()}).Error
+	})
+}
+
+// GetUserRegion returns the user's region, or "" if the user doesn't exist
+func (r *GormRepo) GetUserRegion(userID uint) (string, error) {
+	var regions []string
+	if err := r.db.Model(&domain.User{}).Where("id = ?", userID).Pluck("region", &regions).Error; err != nil {
+		return "", err
+	}
+	if len(regions) == 0 {
+		return "", nil
+	}
+	return regions[0], nil
+}
+
+// GetUserServices returns the provider IDs the user subscribes to
+func (r *GormRepo) GetUserServices(userID uint) ([]int, error) {
+	ids := []int{}
+	if err := r.db.Model(&domain.UserService{}).
+		Where("user_id = ?", userID).
+		Order("provider_id").
+		Pluck("provider_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SetUserServices replaces the user's services
+func (r *GormRepo) SetUserServices(userID uint, providerIDs []int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.UserService{}).Error; err != nil {
+			return err
+		}
+		if len(providerIDs) == 0 {
+			return nil
+		}
+		services := make([]domain.UserService, len(providerIDs))
+		for i, id := range providerIDs {
+			services[i] = domain.UserService{UserID: userID, ProviderID: id}
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&services).Error
+	})
+}
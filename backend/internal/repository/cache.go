@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Shared cache
+
+func (r *GormRepo) GetCache(key string) ([]byte, bool, error) {
+	var entry domain.CacheEntry
+	if err := r.db.Where("cache_key = ? AND expires_at > ?", key, time.Now()).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return entry.Value, true, nil
+}
+
+func (r *GormRepo) SetCache(key string, value []byte, ttl time.Duration) error {
+	entry := domain.CacheEntry{Key: key, Value: value, ExpiresAt: time.Now().Add(ttl)}
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&entry).Error
+}
+
+// PurgeExpiredCache deletes entries that expired before the given time
+func (r *GormRepo) PurgeExpiredCache(before time.Time) (int64, error) {
+	res := r.db.Where("expires_at < ?", before).Delete(&domain.CacheEntry{})
+	return res.RowsAffected, res.Error
+}
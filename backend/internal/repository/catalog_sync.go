@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Catalog sync
+
+// syncItemBatchSize bounds the rows per INSERT when staging export IDs
+const syncItemBatchSize = 500
+
+// GetLatestSyncRun returns the most recent run for an export file, or nil
+func (r *GormRepo) GetLatestSyncRun(exportFile string) (*domain.CatalogSyncRun, error) {
+	var run domain.CatalogSyncRun
+	if err := r.db.Where("export_file = ?", exportFile).Order("id DESC").First(&run).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *GormRepo) SaveSyncRun(run *domain.CatalogSyncRun) error {
+	return r.db.Save(run).Error
+}
+
+// UpsertSyncItems stages export IDs, refreshing the popularity and title of
+// ones already staged without touching their fetch state. An ID listed more
+// than once keeps its last entry, as Postgres won't update a row twice in
+// one statement.
+func (r *GormRepo) UpsertSyncItems(items []domain.CatalogSyncItem) error {
+	items = dedupeSyncItems(items)
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tmdb_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"original_title", "popularity"}),
+	}).CreateInBatches(items, syncItemBatchSize).Error
+}
+
+// dedupeSyncItems drops all but the last entry for each TMDB ID, keeping
+// the order of those last entries
+func dedupeSyncItems(items []domain.CatalogSyncItem) []domain.CatalogSyncItem {
+	last := make(map[uint]int, len(items))
+	for i, item := range items {
+		last[item.TMDBID] = i
+	}
+	if len(last) == len(items) {
+		return items
+	}
+	out := make([]domain.CatalogSyncItem, 0, len(last))
+	for i, item := range items {
+		if last[item.TMDBID] == i {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// ListSyncItemsToFetch returns staged IDs never fetched or last fetched before
+// staleBefore, most popular first
+func (r *GormRepo) ListSyncItemsToFetch(staleBefore time.Time, limit int) ([]domain.CatalogSyncItem, error) {
+	var items []domain.CatalogSyncItem
+	err := r.db.Where("fetched_at IS NULL OR fetched_at < ?", staleBefore).
+		Order("popularity DESC, tmdb_id").Limit(limit).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MarkSyncItemFetched records a fetch attempt; errMsg is empty on success
+func (r *GormRepo) MarkSyncItemFetched(tmdbID uint, at time.Time, errMsg string) error {
+	return r.db.Model(&domain.CatalogSyncItem{}).Where("tmdb_id = ?", tmdbID).
+		Updates(map[string]any{"fetched_at": at, "error": errMsg}).Error
+}
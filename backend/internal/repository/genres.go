@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"slices"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Genres
+
+// ListGenres returns genres by name; mediaType "movie" or "tv" keeps only
+// that TMDB list's genres
+func (r *GormRepo) ListGenres(mediaType string) ([]domain.Genre, error) {
+	q := r.db.Order("name")
+	switch mediaType {
+	case "movie":
+		q = q.Where("movie")
+	case "tv":
+		q = q.Where("tv")
+	}
+	var genres []domain.Genre
+	if err := q.Find(&genres).Error; err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+// UpsertGenres adds genres and renames existing ones. The Movie and TV flags
+// are only ever set, so merging TMDB's two lists in separate calls is safe.
+func (r *GormRepo) UpsertGenres(genres []domain.Genre) error {
+	if len(genres) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "name"}, Value: clause.Column{Table: "excluded", Name: "name"}},
+			{Column: clause.Column{Name: "movie"}, Value: gorm.Expr("genres.movie OR excluded.movie")},
+			{Column: clause.Column{Name: "tv"}, Value: gorm.Expr("genres.tv OR excluded.tv")},
+		},
+	}).Create(&genres).Error
+}
+
+// ListMoviesByGenre returns movies having every one of genreIDs, most popular first
+func (r *GormRepo) ListMoviesByGenre(genreIDs []uint, limit, offset int) ([]domain.Movie, error) {
+	slices.Sort(genreIDs)
+	genreIDs = slices.Compact(genreIDs)
+	var movies []domain.Movie
+	q := r.db.Preload("Genres")
+	if len(genreIDs) > 0 {
+		q = q.Where("id IN (?)", r.db.Model(&domain.MovieGenre{}).Select("movie_id").
+			Where("genre_id IN ?", genreIDs).Group("movie_id").Having("COUNT(*) = ?", len(genreIDs)))
+	}
+	if err := q.Order("popularity DESC, id").Limit(limit).Offset(offset).Find(&movies).Error; err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// setMovieGenresTx replaces a movie's genres, skipping IDs not in the Genre table
+func setMovieGenresTx(tx *gorm.DB, movieID uint, genres []domain.Genre) error {
+	if err := tx.Where("movie_id = ?", movieID).Delete(&domain.MovieGenre{}).Error; err != nil {
+		return err
+	}
+	if len(genres) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(genres))
+	for i, g := range genres {
+		ids[i] = g.ID
+	}
+	return tx.Exec("INSERT INTO movie_genres (movie_id, genre_id) SELECT ?, id FROM genres WHERE id IN ? ON CONFLICT DO NOTHING",
+		movieID, ids).Error
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/HMZ-H/moviemate/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormRepo struct {
@@ -62,6 +66,64 @@ func (r *GormRepo) GetByEmail(email string) (*domain.User, error) {
 	return &user, nil
 }
 
+// UpdateProfile applies the non-nil fields of patch to the user
+func (r *GormRepo) UpdateProfile(id uint, patch domain.ProfilePatch) error {
+	updates := map[string]interface{}{}
+	if patch.Region != nil {
+		updates["region"] = *patch.Region
+	}
+	if patch.EmailReminders != nil {
+		updates["email_reminders"] = *patch.EmailReminders
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	res := r.db.Model(&domain.User{}).Where("id = ?", id).Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetUserRole returns the user's role, or "" if the user doesn't exist
+func (r *GormRepo) GetUserRole(id uint) (string, error) {
+	var roles []string
+	if err := r.db.Model(&domain.User{}).Where("id = ?", id).Pluck("role", &roles).Error; err != nil {
+		return "", err
+	}
+	if len(roles) == 0 {
+		return "", nil
+	}
+	return roles[0], nil
+}
+
+// GetUsername returns the user's name, or "" if the user doesn't exist
+func (r *GormRepo) GetUsername(id uint) (string, error) {
+	var names []string
+	if err := r.db.Model(&domain.User{}).Where("id = ?", id).Pluck("username", &names).Error; err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	return names[0], nil
+}
+
+// SetUserRole changes what the user may do, e.g. promoting them to admin
+func (r *GormRepo) SetUserRole(id uint, role string) error {
+	res := r.db.Model(&domain.User{}).Where("id = ?", id).Update("role", role)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // Movies
 
 func (r *GormRepo) CreateMovie(movie *domain.Movie) error {
@@ -70,7 +132,7 @@ func (r *GormRepo) CreateMovie(movie *domain.Movie) error {
 
 func (r *GormRepo) GetMovieByID(id uint) (*domain.Movie, error) {
 	var movie domain.Movie
-	if err := r.db.First(&movie, id).Error; err != nil {
+	if err := r.db.Preload("Genres").First(&movie, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -79,53 +141,593 @@ func (r *GormRepo) GetMovieByID(id uint) (*domain.Movie, error) {
 	return &movie, nil
 }
 
-func (r *GormRepo) GetMovieByTitle(title string) (*domain.Movie, error) {
-	var movie domain.Movie
-	if err := r.db.Where("title = ?", title).First(&movie).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
+// FindMoviesByTitle matches titles case-insensitively, most popular first.
+// A title can belong to several movies, e.g. remakes or a film and a show.
+func (r *GormRepo) FindMoviesByTitle(title string, year int) ([]domain.Movie, error) {
+	var movies []domain.Movie
+	q := r.db.Preload("Genres").Where("LOWER(title) = LOWER(?)", title)
+	if year > 0 {
+		q = q.Where("year = ?", year)
+	}
+	if err := q.Order("popularity DESC, id").Find(&movies).Error; err != nil {
 		return nil, err
 	}
-	return &movie, nil
+	return movies, nil
 }
 
-func (r *GormRepo) ListMovies(limit, offset int) ([]domain.Movie, error) {
+// ListMovies pages through stored movies, newest first, and counts them all
+func (r *GormRepo) ListMovies(q domain.MovieListQuery) ([]domain.Movie, int64, error) {
+	db := r.db.Model(&domain.Movie{})
+	if q.IncludeDeleted {
+		db = db.Unscoped()
+	}
+	if q.Title != "" {
+		db = db.Where("LOWER(title) LIKE ?", "%"+likeEscaper.Replace(strings.ToLower(q.Title))+"%")
+	}
+	db = db.Session(&gorm.Session{}) // reused for the count and the page
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 	var movies []domain.Movie
-	if err := r.db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&movies).Error; err != nil {
-		return nil, err
+	if err := db.Preload("Genres").Limit(q.Limit).Offset(q.Offset).Order("created_at DESC, id DESC").Find(&movies).Error; err != nil {
+		return nil, 0, err
 	}
-	return movies, nil
+	return movies, total, nil
+}
+
+// UpsertMovie stores a movie keyed by its external IDs. The movie matching
+// its TMDB ID (within its media type) or IMDb ID gets the non-zero fields and
+// any external ID it lacked; with no match, or no external IDs, it's created.
+// Non-nil Genres and Credits replace the stored ones. movie is reloaded from
+// the stored row, without credits.
+// A deleted match is updated but stays deleted.
+func (r *GormRepo) UpsertMovie(movie *domain.Movie) (bool, error) {
+	created := false
+	err := retryTx(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			before, err := upsertMovieTx(tx, movie)
+			created = before == nil
+			return err
+		})
+	})
+	return created, err
+}
+
+// upsertMovieTx does UpsertMovie's work and returns the matched movie as it
+// was before the update, nil if movie was created
+func upsertMovieTx(tx *gorm.DB, movie *domain.Movie) (*domain.Movie, error) {
+	genres, credits := movie.Genres, movie.Credits
+	var conds []string
+	var args []any
+	if movie.TMDBID != nil {
+		conds = append(conds, "(media_type = ? AND tmdb_id = ?)")
+		args = append(args, movie.MediaType, *movie.TMDBID)
+	}
+	if movie.IMDbID != nil {
+		conds = append(conds, "imdb_id = ?")
+		args = append(args, *movie.IMDbID)
+	}
+	var matches []domain.Movie
+	if len(conds) > 0 {
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Genres").
+			Where(strings.Join(conds, " OR "), args...).Find(&matches).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	var before *domain.Movie
+	switch len(matches) {
+	case 0:
+		if err := tx.Omit("Genres", "Credits").Create(movie).Error; err != nil {
+			if isUniqueViolation(err) {
+				return nil, fmt.Errorf("%w: %v", errInsertRaced, err)
+			}
+			return nil, err
+		}
+	case 1:
+		before = &matches[0]
+		movie.ID = before.ID
+		if err := tx.Unscoped().Model(&domain.Movie{ID: movie.ID}).Omit("Genres", "Credits").Updates(movie).Error; err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrExternalIDConflict
+	}
+	if genres != nil {
+		if err := setMovieGenresTx(tx, movie.ID, genres); err != nil {
+			return nil, err
+		}
+	}
+	if credits != nil {
+		if err := setMovieCreditsTx(tx, movie.ID, credits); err != nil {
+			return nil, err
+		}
+	}
+	movie.Genres, movie.Credits = nil, nil
+	return before, tx.Unscoped().Preload("Genres").First(movie, movie.ID).Error
 }
 
 // Watchlist
-func (r *GormRepo) AddWatchlist(item *domain.WatchlistItem) error {
-	item.AddedAt = time.Now()
-	return r.db.Create(item).Error
+
+// watchlistOrder sorts by rank; legacy rows share rank 0 and fall back to newest first
+const watchlistOrder = "watchlist_items.rank ASC, watchlist_items.id DESC"
+
+// minRankGap is the smallest gap left between neighbours before ranks are renumbered
+const minRankGap = 1e-9
+
+// inList scopes watchlist_items to one list
+func inList(list domain.ListRef) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("watchlist_items.user_id = ? AND watchlist_items.list_id = ?", list.OwnerID, list.ListID)
+	}
 }
-func (r *GormRepo) RemoveWatchlist(userID, movieID uint) error {
-	return r.db.Where("user_id = ? AND movie_id = ?", userID, movieID).Delete(&domain.WatchlistItem{}).Error
+
+// AddWatchlist inserts the item at the top of its list. Adding a
+// title that's already saved is a no-op and reports created=false.
+func (r *GormRepo) AddWatchlist(item *domain.WatchlistItem) (bool, error) {
+	var created bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		created, err = addWatchlistTx(tx, item)
+		return err
+	})
+	return created, err
 }
-func (r *GormRepo) ListWatchlistByUser(userID uint) ([]domain.Movie, error) {
-	var movies []domain.Movie
-	// join watchlist -> movies
-	if err := r.db.
-		Model(&domain.Movie{}).
-		Joins("join watchlist_items on watchlist_items.movie_id = movies.id").
-		Where("watchlist_items.user_id = ?", userID).
-		Find(&movies).Error; err != nil {
+
+// RemoveWatchlist soft-deletes the item, keeping its tags for an undo, and
+// reports whether it existed
+func (r *GormRepo) RemoveWatchlist(list domain.ListRef, movieID uint) (bool, error) {
+	var removed bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		removed, err = removeWatchlistTx(tx, list, movieID)
+		return err
+	})
+	return removed, err
+}
+
+// BulkWatchlist adds or removes many titles in one transaction. Results are
+// returned in input order; added titles keep their input order at the top.
+func (r *GormRepo) BulkWatchlist(list domain.ListRef, addedBy uint, action string, movieIDs []uint) ([]domain.BulkResult, error) {
+	results := make([]domain.BulkResult, len(movieIDs))
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		switch action {
+		case domain.BulkAdd:
+			for i := len(movieIDs) - 1; i >= 0; i-- {
+				created, err := addWatchlistTx(tx, &domain.WatchlistItem{
+					UserID:    list.OwnerID,
+					ListID:    list.ListID,
+					MovieID:   movieIDs[i],
+					AddedByID: addedBy,
+				})
+				if err != nil {
+					return err
+				}
+				results[i] = domain.BulkResult{MovieID: movieIDs[i], Status: domain.BulkStatusExists}
+				if created {
+					results[i].Status = domain.BulkStatusAdded
+				}
+			}
+		case domain.BulkRemove:
+			for i, id := range movieIDs {
+				removed, err := removeWatchlistTx(tx, list, id)
+				if err != nil {
+					return err
+				}
+				results[i] = domain.BulkResult{MovieID: id, Status: domain.BulkStatusNotFound}
+				if removed {
+					results[i].Status = domain.BulkStatusRemoved
+				}
+			}
+		default:
+			return fmt.Errorf("unknown bulk action %q", action)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
-	return movies, nil
+	return results, nil
+}
+
+// ImportWatchlist adds items to the user's personal list keeping their
+// original AddedAt, oldest first so the most recently added title ends up on
+// top. Returns how many were new.
+func (r *GormRepo) ImportWatchlist(userID uint, items []domain.WatchlistItem) (int, error) {
+	sorted := make([]domain.WatchlistItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].AddedAt.Before(sorted[j].AddedAt) })
+
+	added := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range sorted {
+			sorted[i].UserID = userID
+			sorted[i].ListID = 0
+			sorted[i].AddedByID = userID
+			created, err := addWatchlistTx(tx, &sorted[i])
+			if err != nil {
+				return err
+			}
+			if created {
+				added++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+func addWatchlistTx(tx *gorm.DB, item *domain.WatchlistItem) (bool, error) {
+	if item.AddedAt.IsZero() {
+		item.AddedAt = time.Now()
+	}
+	if item.Priority == "" {
+		item.Priority = domain.PrioritySomeday
+	}
+	list := domain.ListRef{OwnerID: item.UserID, ListID: item.ListID}
+	var top float64
+	if err := tx.Model(&domain.WatchlistItem{}).
+		Scopes(inList(list)).
+		Select("COALESCE(MIN(rank), 1)").
+		Scan(&top).Error; err != nil {
+		return false, err
+	}
+	item.Rank = top - 1
+
+	// A removed title's row stays behind until purged and still holds the
+	// unique index, so adding it again reuses the row as if it were new
+	var tomb domain.WatchlistItem
+	if err := tx.Unscoped().Scopes(inList(list)).
+		Where("movie_id = ? AND deleted_at IS NOT NULL", item.MovieID).
+		Limit(1).Find(&tomb).Error; err != nil {
+		return false, err
+	}
+	if tomb.ID != 0 {
+		if err := tx.Where("watchlist_item_id = ?", tomb.ID).Delete(&domain.WatchlistTag{}).Error; err != nil {
+			return false, err
+		}
+		if err := tx.Unscoped().Model(&tomb).Updates(map[string]interface{}{
+			"added_by_id": item.AddedByID,
+			"rank":        item.Rank,
+			"priority":    item.Priority,
+			"notes":       item.Notes,
+			"added_at":    item.AddedAt,
+			"deleted_at":  nil,
+		}).Error; err != nil {
+			return false, err
+		}
+		item.ID = tomb.ID
+		return true, recordChangeTx(tx, list, item.MovieID, domain.ChangeAdded)
+	}
+
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(item)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, recordChangeTx(tx, list, item.MovieID, domain.ChangeAdded)
 }
 
-// ListWatchlistIDsByUser returns TMDB movie IDs stored in watchlist_items.movie_id
-func (r *GormRepo) ListWatchlistIDsByUser(userID uint) ([]uint, error) {
+func removeWatchlistTx(tx *gorm.DB, list domain.ListRef, movieID uint) (bool, error) {
+	res := tx.Scopes(inList(list)).Where("movie_id = ?", movieID).Delete(&domain.WatchlistItem{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, recordChangeTx(tx, list, movieID, domain.ChangeRemoved)
+}
+
+// RestoreWatchlist undoes removals made at or after removedSince, putting
+// titles back where they were with their notes and tags. Results are in input
+// order; titles not removed in that window are reported not_found.
+func (r *GormRepo) RestoreWatchlist(list domain.ListRef, movieIDs []uint, removedSince time.Time) ([]domain.BulkResult, error) {
+	results := make([]domain.BulkResult, len(movieIDs))
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range movieIDs {
+			res := tx.Unscoped().Model(&domain.WatchlistItem{}).
+				Scopes(inList(list)).
+				Where("movie_id = ? AND deleted_at >= ?", id, removedSince).
+				Update("deleted_at", nil)
+			if res.Error != nil {
+				return res.Error
+			}
+			results[i] = domain.BulkResult{MovieID: id, Status: domain.BulkStatusNotFound}
+			if res.RowsAffected == 0 {
+				continue
+			}
+			results[i].Status = domain.BulkStatusRestored
+			if err := recordChangeTx(tx, list, id, domain.ChangeAdded); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// PurgeWatchlistTombstones permanently deletes items removed before
+// removedBefore, with their tags
+func (r *GormRepo) PurgeWatchlistTombstones(removedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		tombs := tx.Unscoped().Model(&domain.WatchlistItem{}).Select("id").Where("deleted_at < ?", removedBefore)
+		if err := tx.Where("watchlist_item_id IN (?)", tombs).Delete(&domain.WatchlistTag{}).Error; err != nil {
+			return err
+		}
+		res := tx.Unscoped().Where("deleted_at < ?", removedBefore).Delete(&domain.WatchlistItem{})
+		purged = res.RowsAffected
+		return res.Error
+	})
+	return purged, err
+}
+
+// ListWatchlistByUser returns the user's personal watchlist rows in rank order.
+// movie_id holds TMDB IDs, so callers hydrate them via the metadata cache.
+func (r *GormRepo) ListWatchlistByUser(userID uint, filter domain.WatchlistFilter) ([]domain.WatchlistItem, error) {
+	var items []domain.WatchlistItem
+	if err := r.watchlistScope(domain.ListRef{OwnerID: userID}, filter).
+		Select("watchlist_items.*").
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+		Order(watchlistOrder).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListWatchlistTags returns a list's tags with usage counts, most used first
+func (r *GormRepo) ListWatchlistTags(list domain.ListRef) ([]domain.TagCount, error) {
+	var tags []domain.TagCount
+	if err := r.db.Model(&domain.WatchlistTag{}).
+		Select("watchlist_tags.name, COUNT(*) AS count").
+		Joins("JOIN watchlist_items ON watchlist_items.id = watchlist_tags.watchlist_item_id").
+		Scopes(inList(list)).
+		Where("watchlist_items.deleted_at IS NULL").
+		Group("watchlist_tags.name").
+		Order("count DESC, name").
+		Scan(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ListWatchlistIDs returns TMDB movie IDs stored in watchlist_items.movie_id
+func (r *GormRepo) ListWatchlistIDs(list domain.ListRef) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&domain.WatchlistItem{}).
+		Scopes(inList(list)).
+		Order(watchlistOrder).
+		Pluck("movie_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListWatchlistIDsNeedingMetadata returns the list's titles with no cached
+// metadata or metadata fetched before staleBefore
+func (r *GormRepo) ListWatchlistIDsNeedingMetadata(list domain.ListRef, staleBefore time.Time) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&domain.WatchlistItem{}).
+		Joins("LEFT JOIN movie_metadata ON movie_metadata.tmdb_id = watchlist_items.movie_id").
+		Scopes(inList(list)).
+		Where("movie_metadata.tmdb_id IS NULL OR movie_metadata.fetched_at < ?", staleBefore).
+		Pluck("watchlist_items.movie_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListWatchlistIDsNeedingOffers returns the list's titles whose watch offers
+// were never fetched or were fetched before staleBefore
+func (r *GormRepo) ListWatchlistIDsNeedingOffers(list domain.ListRef, staleBefore time.Time) ([]uint, error) {
 	var ids []uint
 	if err := r.db.Model(&domain.WatchlistItem{}).
-		Where("user_id = ?", userID).
+		Joins("LEFT JOIN watch_offers_fetches ON watch_offers_fetches.tmdb_id = watchlist_items.movie_id").
+		Scopes(inList(list)).
+		Where("watch_offers_fetches.tmdb_id IS NULL OR watch_offers_fetches.fetched_at < ?", staleBefore).
+		Pluck("watchlist_items.movie_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListPersonalWatchlists returns the personal list items of several users
+func (r *GormRepo) ListPersonalWatchlists(userIDs []uint) ([]domain.WatchlistItem, error) {
+	var items []domain.WatchlistItem
+	if len(userIDs) == 0 {
+		return items, nil
+	}
+	if err := r.db.Where("user_id IN ? AND list_id = 0", userIDs).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *GormRepo) UpdateWatchlistItem(list domain.ListRef, movieID uint, patch domain.WatchlistItemPatch) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var item domain.WatchlistItem
+		if err := tx.Scopes(inList(list)).Where("movie_id = ?", movieID).First(&item).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if patch.Priority != nil {
+			updates["priority"] = *patch.Priority
+		}
+		if patch.Notes != nil {
+			updates["notes"] = *patch.Notes
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&item).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		if patch.Tags != nil {
+			if err := tx.Where("watchlist_item_id = ?", item.ID).Delete(&domain.WatchlistTag{}).Error; err != nil {
+				return err
+			}
+			if len(*patch.Tags) > 0 {
+				tags := make([]domain.WatchlistTag, 0, len(*patch.Tags))
+				for _, name := range *patch.Tags {
+					tags = append(tags, domain.WatchlistTag{WatchlistItemID: item.ID, UserID: list.OwnerID, Name: name})
+				}
+				if err := tx.Create(&tags).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return recordChangeTx(tx, list, movieID, domain.ChangeUpdated)
+	})
+}
+
+// MoveWatchlistItem places movieID directly after afterID (or at the top when
+// afterID is 0). Only the moved row is rewritten, taking the midpoint of its new
+// neighbours' ranks; the list is renumbered only when that gap is exhausted,
+// recording a change for every row whose rank moved.
+func (r *GormRepo) MoveWatchlistItem(list domain.ListRef, movieID, afterID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var items []domain.WatchlistItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Scopes(inList(list)).
+			Order(watchlistOrder).
+			Find(&items).Error; err != nil {
+			return err
+		}
+
+		var moving *domain.WatchlistItem
+		rest := make([]domain.WatchlistItem, 0, len(items))
+		for i := range items {
+			if items[i].MovieID == movieID {
+				moving = &items[i]
+				continue
+			}
+			rest = append(rest, items[i])
+		}
+		if moving == nil {
+			return ErrNotFound
+		}
+
+		pos := 0
+		if afterID != 0 {
+			pos = -1
+			for i, it := range rest {
+				if it.MovieID == afterID {
+					pos = i + 1
+					break
+				}
+			}
+			if pos < 0 {
+				return ErrNotFound
+			}
+		}
+
+		if rank, ok := rankBetween(rest, pos); ok {
+			if err := tx.Model(moving).Update("rank", rank).Error; err != nil {
+				return err
+			}
+			return recordChangeTx(tx, list, movieID, domain.ChangeUpdated)
+		}
+
+		// Gap exhausted (or legacy ties): renumber the whole list once
+		ordered := make([]domain.WatchlistItem, 0, len(items))
+		ordered = append(ordered, rest[:pos]...)
+		ordered = append(ordered, *moving)
+		ordered = append(ordered, rest[pos:]...)
+		movedRecorded := false
+		for i, it := range ordered {
+			rank := float64(i + 1)
+			if it.Rank == rank {
+				continue
+			}
+			if err := tx.Model(&domain.WatchlistItem{}).Where("id = ?", it.ID).Update("rank", rank).Error; err != nil {
+				return err
+			}
+			// Clients sync ranks from the change feed, so every renumbered row is a change
+			if err := recordChangeTx(tx, list, it.MovieID, domain.ChangeUpdated); err != nil {
+				return err
+			}
+			movedRecorded = movedRecorded || it.MovieID == movieID
+		}
+		if movedRecorded {
+			return nil
+		}
+		return recordChangeTx(tx, list, movieID, domain.ChangeUpdated)
+	})
+}
+
+// rankBetween returns a rank that sorts an item at index pos of list
+func rankBetween(list []domain.WatchlistItem, pos int) (float64, bool) {
+	switch {
+	case len(list) == 0:
+		return 0, true
+	case pos == 0:
+		return list[0].Rank - 1, true
+	case pos == len(list):
+		return list[pos-1].Rank + 1, true
+	}
+	prev, next := list[pos-1].Rank, list[pos].Rank
+	if next-prev < minRankGap {
+		return 0, false
+	}
+	return prev + (next-prev)/2, true
+}
+
+// Diary
+
+// AddDiaryEntries inserts entries, skipping viewings already recorded for the
+// same title and date. Returns how many were new.
+func (r *GormRepo) AddDiaryEntries(entries []domain.DiaryEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&entries, 200)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	return int(res.RowsAffected), nil
+}
+
+func (r *GormRepo) ListWatchedIDs(userIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(userIDs) == 0 {
+		return ids, nil
+	}
+	if err := r.db.Model(&domain.DiaryEntry{}).
+		Where("user_id IN ?", userIDs).
+		Distinct().
 		Pluck("movie_id", &ids).Error; err != nil {
 		return nil, err
 	}
 	return ids, nil
 }
+
+// Metadata cache
+func (r *GormRepo) GetMetadataByIDs(ids []uint) ([]domain.MovieMetadata, error) {
+	var rows []domain.MovieMetadata
+	if len(ids) == 0 {
+		return rows, nil
+	}
+	if err := r.db.Where("tmdb_id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *GormRepo) UpsertMetadata(m *domain.MovieMetadata) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(m).Error
+}
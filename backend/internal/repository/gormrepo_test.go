@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+func TestRankBetween(t *testing.T) {
+	ranks := func(rs ...float64) []domain.WatchlistItem {
+		items := make([]domain.WatchlistItem, len(rs))
+		for i, r := range rs {
+			items[i].Rank = r
+		}
+		return items
+	}
+	tests := []struct {
+		name   string
+		list   []domain.WatchlistItem
+		pos    int
+		want   float64
+		wantOK bool
+	}{
+		{"empty list", nil, 0, 0, true},
+		{"top", ranks(1, 2, 3), 0, 0, true},
+		{"bottom", ranks(1, 2, 3), 3, 4, true},
+		{"between", ranks(1, 2, 3), 1, 1.5, true},
+		{"between negatives", ranks(-4, -2), 1, -3, true},
+		{"legacy rows share rank 0", ranks(0, 0), 1, 0, false},
+		{"gap too small", ranks(1, 1+minRankGap/2), 1, 0, false},
+		{"gap just big enough", ranks(1, 1+minRankGap*2), 1, 1 + minRankGap, true},
+	}
+	for _, tt := range tests {
+		got, ok := rankBetween(tt.list, tt.pos)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("%s: rankBetween = %v, %v; want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+		if !ok {
+			continue
+		}
+		if tt.pos > 0 && got <= tt.list[tt.pos-1].Rank {
+			t.Errorf("%s: rank %v doesn't sort after %v", tt.name, got, tt.list[tt.pos-1].Rank)
+		}
+		if tt.pos < len(tt.list) && got >= tt.list[tt.pos].Rank {
+			t.Errorf("%s: rank %v doesn't sort before %v", tt.name, got, tt.list[tt.pos].Rank)
+		}
+	}
+}
+
+func TestDedupeSyncItems(t *testing.T) {
+	items := []domain.CatalogSyncItem{
+		{TMDBID: 1, Popularity: 1},
+		{TMDBID: 2, Popularity: 2},
+		{TMDBID: 1, Popularity: 3},
+		{TMDBID: 3, Popularity: 4},
+	}
+	got := dedupeSyncItems(items)
+	want := []domain.CatalogSyncItem{
+		{TMDBID: 2, Popularity: 2},
+		{TMDBID: 1, Popularity: 3},
+		{TMDBID: 3, Popularity: 4},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].TMDBID != want[i].TMDBID || got[i].Popularity != want[i].Popularity {
+			t.Errorf("item %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got := dedupeSyncItems(want); len(got) != len(want) {
+		t.Errorf("unique items were dropped: %+v", got)
+	}
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Shared lists
+
+func (r *GormRepo) CreateList(list *domain.Watchlist) error {
+	return r.db.Create(list).Error
+}
+
+// GetList returns a list with its members, or nil if it doesn't exist
+func (r *GormRepo) GetList(id uint) (*domain.Watchlist, error) {
+	var list domain.Watchlist
+	if err := r.db.First(&list, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var rows []struct {
+		domain.WatchlistMember
+		Username string
+	}
+	if err := r.db.Model(&domain.WatchlistMember{}).
+		Select("watchlist_members.*, users.username").
+		Joins("JOIN users ON users.id = watchlist_members.user_id").
+		Where("watchlist_members.watchlist_id = ?", id).
+		Order("watchlist_members.created_at").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	list.Members = make([]domain.WatchlistMember, len(rows))
+	for i, row := range rows {
+		list.Members[i] = row.WatchlistMember
+		list.Members[i].Username = row.Username
+	}
+	return &list, nil
+}
+
+// ListsForUser returns lists the user owns or is a member of, with their role
+func (r *GormRepo) ListsForUser(userID uint) ([]domain.ListAccess, error) {
+	var owned []domain.Watchlist
+	if err := r.db.Where("owner_id = ?", userID).Order("created_at").Find(&owned).Error; err != nil {
+		return nil, err
+	}
+	var shared []struct {
+		domain.Watchlist
+		Role string
+	}
+	if err := r.db.Model(&domain.Watchlist{}).
+		Select("watchlists.*, watchlist_members.role").
+		Joins("JOIN watchlist_members ON watchlist_members.watchlist_id = watchlists.id").
+		Where("watchlist_members.user_id = ?", userID).
+		Order("watchlists.created_at").
+		Scan(&shared).Error; err != nil {
+		return nil, err
+	}
+
+	lists := make([]domain.ListAccess, 0, len(owned)+len(shared))
+	for _, l := range owned {
+		lists = append(lists, domain.ListAccess{Watchlist: l, Role: domain.RoleOwner})
+	}
+	for _, l := range shared {
+		lists = append(lists, domain.ListAccess{Watchlist: l.Watchlist, Role: l.Role})
+	}
+	return lists, nil
+}
+
+func (r *GormRepo) RenameList(id uint, name string) error {
+	return r.db.Model(&domain.Watchlist{}).Where("id = ?", id).Update("name", name).Error
+}
+
+// SetListSharing updates a list's visibility, public slug and whether the
+// public link shows notes and tags; a nil slug revokes the public link
+func (r *GormRepo) SetListSharing(id uint, visibility string, slug *string, shareNotes bool) error {
+	return r.db.Model(&domain.Watchlist{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"visibility": visibility, "public_slug": slug, "share_notes": shareNotes}).Error
+}
+
+// GetPersonalSharing returns the sharing settings of a user's personal list
+// as a Watchlist with ID 0. The list is private until first shared.
+func (r *GormRepo) GetPersonalSharing(userID uint) (*domain.Watchlist, error) {
+	var sharing domain.PersonalListSharing
+	if err := r.db.Where("user_id = ?", userID).First(&sharing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &domain.Watchlist{OwnerID: userID, Name: domain.PersonalListName, Visibility: domain.VisibilityPrivate}, nil
+		}
+		return nil, err
+	}
+	return personalList(sharing), nil
+}
+
+// SetPersonalSharing is SetListSharing for a user's personal list
+func (r *GormRepo) SetPersonalSharing(userID uint, visibility string, slug *string, shareNotes bool) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"visibility", "public_slug", "share_notes", "updated_at"}),
+	}).Create(&domain.PersonalListSharing{UserID: userID, Visibility: visibility, PublicSlug: slug, ShareNotes: shareNotes}).Error
+}
+
+// GetPublicList finds a public list by its slug, or nil if there is none.
+// A personal list comes back with ID 0.
+func (r *GormRepo) GetPublicList(slug string) (*domain.Watchlist, error) {
+	var list domain.Watchlist
+	err := r.db.Where("public_slug = ? AND visibility = ?", slug, domain.VisibilityPublic).First(&list).Error
+	if err == nil {
+		return &list, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var sharing domain.PersonalListSharing
+	if err := r.db.Where("public_slug = ? AND visibility = ?", slug, domain.VisibilityPublic).First(&sharing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return personalList(sharing), nil
+}
+
+// personalList presents a personal list's sharing settings as a Watchlist
+func personalList(sharing domain.PersonalListSharing) *domain.Watchlist {
+	return &domain.Watchlist{
+		OwnerID:    sharing.UserID,
+		Name:       domain.PersonalListName,
+		Visibility: sharing.Visibility,
+		PublicSlug: sharing.PublicSlug,
+		ShareNotes: sharing.ShareNotes,
+		UpdatedAt:  sharing.UpdatedAt,
+	}
+}
+
+// DeleteList removes a list together with its items, members and invites
+func (r *GormRepo) DeleteList(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		items := tx.Unscoped().Model(&domain.WatchlistItem{}).Select("id").Where("list_id = ?", id)
+		if err := tx.Where("watchlist_item_id IN (?)", items).Delete(&domain.WatchlistTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("list_id = ?", id).Delete(&domain.WatchlistItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("list_id = ?", id).Delete(&domain.WatchlistChange{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("list_id = ?", id).Delete(&domain.WatchlistVersion{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("watchlist_id = ?", id).Delete(&domain.WatchlistMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("watchlist_id = ?", id).Delete(&domain.WatchlistInvite{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Watchlist{}, id).Error
+	})
+}
+
+// GetListRole returns the list and the user's role on it. The list is nil if
+// it doesn't exist; the role is empty if the user has no access.
+func (r *GormRepo) GetListRole(listID, userID uint) (*domain.Watchlist, string, error) {
+	var list domain.Watchlist
+	if err := r.db.First(&list, listID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	if list.OwnerID == userID {
+		return &list, domain.RoleOwner, nil
+	}
+	var member domain.WatchlistMember
+	if err := r.db.Where("watchlist_id = ? AND user_id = ?", listID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &list, "", nil
+		}
+		return nil, "", err
+	}
+	return &list, member.Role, nil
+}
+
+func (r *GormRepo) SetMemberRole(listID, userID uint, role string) error {
+	res := r.db.Model(&domain.WatchlistMember{}).
+		Where("watchlist_id = ? AND user_id = ?", listID, userID).
+		Update("role", role)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormRepo) RemoveMember(listID, userID uint) (bool, error) {
+	res := r.db.Where("watchlist_id = ? AND user_id = ?", listID, userID).Delete(&domain.WatchlistMember{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *GormRepo) CreateInvite(invite *domain.WatchlistInvite) error {
+	return r.db.Create(invite).Error
+}
+
+// AcceptInvite consumes an unexpired invite and adds the user to its list.
+// An existing member keeps the higher of their current and invited role, and
+// the owner accepting their own invite leaves it unused. Unknown, used or
+// expired tokens return ErrNotFound.
+func (r *GormRepo) AcceptInvite(tokenHash string, userID uint) (*domain.ListAccess, error) {
+	var access domain.ListAccess
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var invite domain.WatchlistInvite
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND accepted_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+			First(&invite).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if err := tx.First(&access.Watchlist, invite.WatchlistID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if access.OwnerID == userID {
+			access.Role = domain.RoleOwner
+			return nil
+		}
+
+		access.Role = invite.Role
+		var existing domain.WatchlistMember
+		err := tx.Where("watchlist_id = ? AND user_id = ?", invite.WatchlistID, userID).First(&existing).Error
+		switch {
+		case err == nil:
+			if domain.RoleAtLeast(existing.Role, invite.Role) {
+				access.Role = existing.Role
+			} else if err := tx.Model(&existing).Update("role", invite.Role).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			member := domain.WatchlistMember{WatchlistID: invite.WatchlistID, UserID: userID, Role: invite.Role}
+			if err := tx.Create(&member).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		now := time.Now()
+		return tx.Model(&invite).Updates(map[string]interface{}{"accepted_by_id": userID, "accepted_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &access, nil
+}
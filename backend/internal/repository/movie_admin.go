@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Admin movie edits. Every change is logged to movie_audits in the same
+// transaction, so the log can't disagree with the catalog.
+
+// GetMovieIncludingDeleted is GetMovieByID that also finds deleted movies
+func (r *GormRepo) GetMovieIncludingDeleted(id uint) (*domain.Movie, error) {
+	var movie domain.Movie
+	if err := r.db.Unscoped().Preload("Genres").First(&movie, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &movie, nil
+}
+
+// AdminUpsertMovie is UpsertMovie on behalf of userID: a deleted match is
+// restored, and the change is recorded
+func (r *GormRepo) AdminUpsertMovie(movie *domain.Movie, userID uint) (bool, error) {
+	created := false
+	err := retryTx(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			before, err := upsertMovieTx(tx, movie)
+			if err != nil {
+				return err
+			}
+			if before == nil {
+				created = true
+				return recordMovieAuditTx(tx, movie.ID, userID, domain.MovieAuditCreated, movieChanges(domain.Movie{}, *movie))
+			}
+			if movie.DeletedAt.Valid {
+				if err := tx.Unscoped().Model(&domain.Movie{ID: movie.ID}).Update("deleted_at", nil).Error; err != nil {
+					return err
+				}
+				movie.DeletedAt = gorm.DeletedAt{}
+				if err := recordMovieAuditTx(tx, movie.ID, userID, domain.MovieAuditRestored, nil); err != nil {
+					return err
+				}
+			}
+			changes := movieChanges(*before, *movie)
+			if len(changes) == 0 {
+				return nil
+			}
+			return recordMovieAuditTx(tx, movie.ID, userID, domain.MovieAuditUpdated, changes)
+		})
+	})
+	return created, err
+}
+
+// UpdateMovie applies the non-nil fields of patch to a movie, deleted or
+// not, and records what changed. It returns ErrExternalIDConflict when the
+// new external IDs belong to another movie.
+func (r *GormRepo) UpdateMovie(id uint, patch domain.MoviePatch, userID uint) (*domain.Movie, error) {
+	var movie domain.Movie
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var before domain.Movie
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Genres").First(&before, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		after := before
+		updates := map[string]interface{}{}
+		if patch.MediaType != nil {
+			after.MediaType = *patch.MediaType
+			updates["media_type"] = after.MediaType
+		}
+		if patch.TMDBID != nil {
+			after.TMDBID = nil
+			if *patch.TMDBID != 0 {
+				after.TMDBID = patch.TMDBID
+			}
+			updates["tmdb_id"] = after.TMDBID
+		}
+		if patch.IMDbID != nil {
+			after.IMDbID = nil
+			if *patch.IMDbID != "" {
+				after.IMDbID = patch.IMDbID
+			}
+			updates["imdb_id"] = after.IMDbID
+		}
+		if patch.Title != nil {
+			updates["title"] = *patch.Title
+		}
+		if patch.OriginalTitle != nil {
+			updates["original_title"] = *patch.OriginalTitle
+		}
+		if patch.Description != nil {
+			updates["description"] = *patch.Description
+		}
+		if patch.Year != nil {
+			updates["year"] = *patch.Year
+		}
+		if patch.CastNames != nil {
+			updates["cast_names"] = domain.StringArray(*patch.CastNames)
+		}
+
+		if patch.MediaType != nil || patch.TMDBID != nil || patch.IMDbID != nil {
+			var conds []string
+			var args []any
+			if after.TMDBID != nil {
+				conds = append(conds, "(media_type = ? AND tmdb_id = ?)")
+				args = append(args, after.MediaType, *after.TMDBID)
+			}
+			if after.IMDbID != nil {
+				conds = append(conds, "imdb_id = ?")
+				args = append(args, *after.IMDbID)
+			}
+			if len(conds) > 0 {
+				var taken int64
+				if err := tx.Unscoped().Model(&domain.Movie{}).Where("id <> ?", id).
+					Where(strings.Join(conds, " OR "), args...).Count(&taken).Error; err != nil {
+					return err
+				}
+				if taken > 0 {
+					return ErrExternalIDConflict
+				}
+			}
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Unscoped().Model(&domain.Movie{ID: id}).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if patch.GenreIDs != nil {
+			genres := make([]domain.Genre, len(*patch.GenreIDs))
+			for i, gid := range *patch.GenreIDs {
+				genres[i] = domain.Genre{ID: gid}
+			}
+			if err := setMovieGenresTx(tx, id, genres); err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Preload("Genres").First(&movie, id).Error; err != nil {
+			return err
+		}
+		changes := movieChanges(before, movie)
+		if len(changes) == 0 {
+			return nil
+		}
+		return recordMovieAuditTx(tx, id, userID, domain.MovieAuditUpdated, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+// DeleteMovie soft-deletes a movie, hiding it from listings and search
+func (r *GormRepo) DeleteMovie(id, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&domain.Movie{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return recordMovieAuditTx(tx, id, userID, domain.MovieAuditDeleted, nil)
+	})
+}
+
+// RestoreMovie undoes DeleteMovie. Restoring a movie that isn't deleted
+// changes nothing.
+func (r *GormRepo) RestoreMovie(id, userID uint) (*domain.Movie, error) {
+	var movie domain.Movie
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Model(&domain.Movie{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			if err := recordMovieAuditTx(tx, id, userID, domain.MovieAuditRestored, nil); err != nil {
+				return err
+			}
+		}
+		err := tx.Preload("Genres").First(&movie, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+// ListMovieAudits returns a movie's audit log, newest first
+func (r *GormRepo) ListMovieAudits(movieID uint, limit int) ([]domain.MovieAudit, error) {
+	var audits []domain.MovieAudit
+	if err := r.db.Where("movie_id = ?", movieID).Order("id DESC").Limit(limit).Find(&audits).Error; err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+// recordMovieAuditTx logs an admin's change to a movie; changes may be nil
+func recordMovieAuditTx(tx *gorm.DB, movieID, userID uint, action string, changes map[string]domain.FieldChange) error {
+	if changes == nil {
+		changes = map[string]domain.FieldChange{}
+	}
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&domain.MovieAudit{
+		MovieID:   movieID,
+		UserID:    userID,
+		Action:    action,
+		Changes:   string(b),
+		CreatedAt: time.Now(),
+	}).Error
+}
+
+// movieChanges lists the editable fields that differ between before and
+// after, keyed by their JSON names
+func movieChanges(before, after domain.Movie) map[string]domain.FieldChange {
+	changes := map[string]domain.FieldChange{}
+	add := func(field string, from, to any) {
+		if !reflect.DeepEqual(from, to) {
+			changes[field] = domain.FieldChange{From: from, To: to}
+		}
+	}
+	add("media_type", before.MediaType, after.MediaType)
+	add("tmdb_id", derefUint(before.TMDBID), derefUint(after.TMDBID))
+	add("imdb_id", derefString(before.IMDbID), derefString(after.IMDbID))
+	add("title", before.Title, after.Title)
+	add("original_title", before.OriginalTitle, after.OriginalTitle)
+	add("description", before.Description, after.Description)
+	add("year", before.Year, after.Year)
+	add("genres", genreIDs(before.Genres), genreIDs(after.Genres))
+	add("cast_names", []string(before.CastNames), []string(after.CastNames))
+	return changes
+}
+
+func derefUint(p *uint) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func derefString(p *string) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// genreIDs returns the sorted IDs of genres, so load order isn't a change
+func genreIDs(genres []domain.Genre) []uint {
+	ids := make([]uint, len(genres))
+	for i, g := range genres {
+		ids[i] = g.ID
+	}
+	slices.Sort(ids)
+	return ids
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+// Movie search
+
+// searchConfig is the text search configuration movies.search_vector is
+// built with; queries must use the same one to match its stemming
+const searchConfig = "english"
+
+// ts_headline doesn't escape the text around matches, so matches are marked
+// with control characters and turned into <mark> tags after escaping
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var (
+	titleHeadlineOptions   = `HighlightAll=true, StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+	snippetHeadlineOptions = `MaxFragments=2, MinWords=12, MaxWords=30, FragmentDelimiter=" … ", StartSel="` +
+		highlightStart + `", StopSel="` + highlightStop + `"`
+	highlightReplacer = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+)
+
+// searchRanking weighs D (unused), C (overview), B (cast) and A (titles).
+// Normalization 1 divides by the document's log length, so a long overview
+// mentioning a word doesn't outrank a title made of it.
+const searchRanking = `ts_rank('{0.1, 0.2, 0.4, 1.0}', search_vector, websearch_to_tsquery(@config, @query), 1)`
+
+// searchPopularity maps popularity onto 0-1 on a log scale; TMDB's runs
+// from near 0 to the low thousands
+const searchPopularity = "LEAST(LN(1 + GREATEST(popularity, 0)) / LN(1001), 1)"
+
+// Fuzzy and autocomplete scores are mostly how well the text matches, with
+// popularity breaking near-ties
+const (
+	searchMatchWeight      = 0.8
+	searchPopularityWeight = 0.2
+)
+
+// fuzzyRanking scores titles by trigram similarity to the query, whole or
+// against their best-matching words, so "godfater" still finds "The Godfather"
+var fuzzyRanking = fmt.Sprintf(`%g * GREATEST(similarity(LOWER(title), @fuzzy),
+	similarity(LOWER(coalesce(original_title, '')), @fuzzy), word_similarity(@fuzzy, LOWER(title))) + %g * %s`,
+	searchMatchWeight, searchPopularityWeight, searchPopularity)
+
+// SearchMovies returns one page of movies matching q, best first, and the
+// total number of matches
+func (r *GormRepo) SearchMovies(q domain.MovieSearchQuery) ([]domain.MovieSearchHit, int64, error) {
+	args := searchArgs(q)
+	where := "search_vector @@ websearch_to_tsquery(@config, @query)" + searchFilters(q, args)
+	return r.searchPage(searchRanking, where, args)
+}
+
+// FuzzySearchMovies matches titles and original titles by trigram
+// similarity, for queries with typos that full-text search misses. Ranking
+// blends similarity with popularity.
+func (r *GormRepo) FuzzySearchMovies(q domain.MovieSearchQuery) ([]domain.MovieSearchHit, int64, error) {
+	args := searchArgs(q)
+	args["fuzzy"] = strings.ToLower(q.Query)
+	where := "(LOWER(title) % @fuzzy OR LOWER(original_title) % @fuzzy OR @fuzzy <% LOWER(title))" + searchFilters(q, args)
+	return r.searchPage(fuzzyRanking, where, args)
+}
+
+func searchArgs(q domain.MovieSearchQuery) map[string]any {
+	return map[string]any{
+		"config":          searchConfig,
+		"query":           q.Query,
+		"limit":           q.Limit,
+		"offset":          q.Offset,
+		"title_options":   titleHeadlineOptions,
+		"snippet_options": snippetHeadlineOptions,
+	}
+}
+
+// searchFilters adds q's year and genre filters to a search's WHERE clause,
+// and leaves out deleted movies
+func searchFilters(q domain.MovieSearchQuery, args map[string]any) string {
+	where := " AND deleted_at IS NULL"
+	if q.Year > 0 {
+		where += " AND year = @year"
+		args["year"] = q.Year
+	}
+	if len(q.GenreIDs) > 0 {
+		where += " AND id IN (SELECT movie_id FROM movie_genres WHERE genre_id IN @genres GROUP BY movie_id HAVING COUNT(*) = @genre_count)"
+		args["genres"] = q.GenreIDs
+		args["genre_count"] = len(q.GenreIDs)
+	}
+	return where
+}
+
+// searchPage ranks the movies matching where and loads one page of them.
+// Headlines are costly, so they are only built for the page being returned;
+// a query with no full-text match, like a fuzzy one, gets the overview's opening.
+func (r *GormRepo) searchPage(ranking, where string, args map[string]any) ([]domain.MovieSearchHit, int64, error) {
+	var rows []struct {
+		ID             uint
+		Rank           float64
+		Total          int64
+		TitleHighlight string
+		Snippet        string
+	}
+	err := r.db.Raw(`SELECT page.id, page.rank, page.total,
+	ts_headline(@config, page.title, websearch_to_tsquery(@config, @query), @title_options) AS title_highlight,
+	ts_headline(@config, coalesce(page.description, ''), websearch_to_tsquery(@config, @query), @snippet_options) AS snippet
+FROM (
+	SELECT id, title, description, popularity, `+ranking+` AS rank, COUNT(*) OVER () AS total
+	FROM movies
+	WHERE `+where+`
+	ORDER BY rank DESC, popularity DESC, id
+	LIMIT @limit OFFSET @offset
+) page
+ORDER BY page.rank DESC, page.popularity DESC, page.id`, args).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		if args["offset"] == 0 {
+			return []domain.MovieSearchHit{}, 0, nil
+		}
+		// Past the last page the window count is lost with the rows
+		var total int64
+		if err := r.db.Raw("SELECT COUNT(*) FROM movies WHERE "+where, args).Scan(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		return []domain.MovieSearchHit{}, total, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var movies []domain.Movie
+	if err := r.db.Preload("Genres").Where("id IN ?", ids).Find(&movies).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint]domain.Movie, len(movies))
+	for _, m := range movies {
+		byID[m.ID] = m
+	}
+	hits := make([]domain.MovieSearchHit, 0, len(rows))
+	for _, row := range rows {
+		m, ok := byID[row.ID]
+		if !ok {
+			continue // deleted since the search
+		}
+		hits = append(hits, domain.MovieSearchHit{
+			Movie:          m,
+			Rank:           row.Rank,
+			TitleHighlight: highlight(row.TitleHighlight),
+			Snippet:        highlight(row.Snippet),
+		})
+	}
+	return hits, rows[0].Total, nil
+}
+
+// likeEscaper escapes LIKE wildcards in user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// suggestArgs holds the patterns autocomplete matches a prefix with: the
+// start of the text, the start of any word in it, or anywhere
+func suggestArgs(prefix string, limit int) map[string]any {
+	p := likeEscaper.Replace(strings.ToLower(prefix))
+	return map[string]any{
+		"q":           strings.ToLower(prefix),
+		"prefix":      p + "%",
+		"word_prefix": "% " + p + "%",
+		"contains":    "%" + p + "%",
+		"limit":       limit,
+	}
+}
+
+// SuggestTitles completes a title prefix. Titles starting with it rank
+// first, then ones with a word starting with it, then trigram matches that
+// forgive typos, each blended with popularity.
+func (r *GormRepo) SuggestTitles(ctx context.Context, prefix string, limit int) ([]domain.TitleSuggestion, error) {
+	suggestions := []domain.TitleSuggestion{}
+	err := r.db.WithContext(ctx).Raw(fmt.Sprintf(`SELECT id, media_type, tmdb_id, title, year,
+	%g * (CASE WHEN LOWER(title) LIKE @prefix THEN 1
+		WHEN LOWER(title) LIKE @word_prefix THEN 0.9
+		ELSE word_similarity(@q, LOWER(title)) * 0.8 END) + %g * %s AS score
+FROM movies
+WHERE deleted_at IS NULL AND (LOWER(title) LIKE @prefix OR LOWER(title) LIKE @word_prefix OR @q <%% LOWER(title))
+ORDER BY score DESC, id
+LIMIT @limit`, searchMatchWeight, searchPopularityWeight, searchPopularity), suggestArgs(prefix, limit)).Scan(&suggestions).Error
+	if err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// SuggestPeople completes a person's first or last name, ranking full
+// name prefixes first and blending in their most popular movie
+func (r *GormRepo) SuggestPeople(ctx context.Context, prefix string, limit int) ([]domain.PersonSuggestion, error) {
+	suggestions := []domain.PersonSuggestion{}
+	err := r.db.WithContext(ctx).Raw(fmt.Sprintf(`SELECT people.id, people.name, people.profile_path, COUNT(DISTINCT movies.id) AS movies,
+	%g * (CASE WHEN LOWER(people.name) LIKE @prefix THEN 1 ELSE 0.9 END) + %g * MAX(%s) AS score
+FROM people
+JOIN credits ON credits.person_id = people.id
+JOIN movies ON movies.id = credits.movie_id AND movies.deleted_at IS NULL
+WHERE LOWER(people.name) LIKE @prefix OR LOWER(people.name) LIKE @word_prefix
+GROUP BY people.id
+ORDER BY score DESC, people.id
+LIMIT @limit`, searchMatchWeight, searchPopularityWeight, searchPopularity), suggestArgs(prefix, limit)).Scan(&suggestions).Error
+	if err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// highlight escapes a ts_headline result and marks its matches
+func highlight(s string) string {
+	return highlightReplacer.Replace(html.EscapeString(s))
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Release reminders and notifications
+
+// ListReminderCandidates returns each user's watchlisted titles that could
+// still be upcoming: movies released in minYear or later, titles with an
+// unknown year and titles whose metadata isn't cached yet. Titles on a
+// shared list count for its owner and every member.
+func (r *GormRepo) ListReminderCandidates(minYear int) ([]domain.ReminderCandidate, error) {
+	var candidates []domain.ReminderCandidate
+	if err := r.db.Model(&domain.WatchlistItem{}).
+		Distinct("users.id AS user_id", "users.username", "users.email", "users.region",
+			"users.email_reminders", "watchlist_items.movie_id").
+		Joins("LEFT JOIN watchlist_members ON watchlist_items.list_id <> 0 AND watchlist_members.watchlist_id = watchlist_items.list_id").
+		Joins("JOIN users ON users.id = watchlist_items.user_id OR users.id = watchlist_members.user_id").
+		Joins("LEFT JOIN movie_metadata ON movie_metadata.tmdb_id = watchlist_items.movie_id").
+		Where("movie_metadata.tmdb_id IS NULL OR (movie_metadata.media_type = ? AND "+
+			"(movie_metadata.release_year = 0 OR movie_metadata.release_year >= ?))", "movie", minYear).
+		Scan(&candidates).Error; err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+func (r *GormRepo) GetReleaseDates(ids []uint) ([]domain.ReleaseDate, error) {
+	var dates []domain.ReleaseDate
+	if len(ids) == 0 {
+		return dates, nil
+	}
+	if err := r.db.Where("tmdb_id IN ?", ids).Find(&dates).Error; err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// ReplaceReleaseDates stores a title's release dates, dropping regions that
+// are no longer listed
+func (r *GormRepo) ReplaceReleaseDates(tmdbID uint, dates []domain.ReleaseDate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tmdb_id = ?", tmdbID).Delete(&domain.ReleaseDate{}).Error; err != nil {
+			return err
+		}
+		if len(dates) == 0 {
+			return nil
+		}
+		return tx.Create(&dates).Error
+	})
+}
+
+// CreateNotification inserts n unless the same reminder already exists.
+// Returns whether it was created.
+func (r *GormRepo) CreateNotification(n *domain.Notification) (bool, error) {
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(n)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *GormRepo) MarkNotificationEmailed(id uint) error {
+	return r.db.Model(&domain.Notification{}).Where("id = ?", id).Update("emailed_at", time.Now()).Error
+}
+
+// ListPendingEmails returns notifications created since createdSince that
+// haven't been emailed, for users who want email reminders
+func (r *GormRepo) ListPendingEmails(createdSince time.Time) ([]domain.PendingEmail, error) {
+	var notes []domain.Notification
+	if err := r.db.Joins("JOIN users ON users.id = notifications.user_id").
+		Where("notifications.emailed_at IS NULL AND notifications.created_at >= ?", createdSince).
+		Where("users.email_reminders AND users.email <> ''").
+		Order("notifications.id").Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, nil
+	}
+	userIDs := make([]uint, 0, len(notes))
+	for _, n := range notes {
+		userIDs = append(userIDs, n.UserID)
+	}
+	var users []domain.User
+	if err := r.db.Select("id", "username", "email", "region", "email_reminders").
+		Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]domain.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	pending := make([]domain.PendingEmail, 0, len(notes))
+	for _, n := range notes {
+		u := byID[n.UserID]
+		pending = append(pending, domain.PendingEmail{
+			To: domain.ReminderCandidate{UserID: u.ID, Username: u.Username, Email: u.Email, Region: u.Region,
+				EmailReminders: u.EmailReminders, MovieID: n.MovieID},
+			Notification: n,
+		})
+	}
+	return pending, nil
+}
+
+// ListNotifications returns the user's newest notifications first
+func (r *GormRepo) ListNotifications(userID uint, unreadOnly bool, limit int) ([]domain.Notification, error) {
+	var notes []domain.Notification
+	q := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+	if err := q.Order("created_at DESC, id DESC").Limit(limit).Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (r *GormRepo) CountUnreadNotifications(userID uint) (int64, error) {
+	var n int64
+	err := r.db.Model(&domain.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&n).Error
+	return n, err
+}
+
+// MarkNotificationRead marks one of the user's notifications read. Marking an
+// already read notification is a no-op; someone else's returns ErrNotFound.
+func (r *GormRepo) MarkNotificationRead(userID, id uint) error {
+	var n domain.Notification
+	res := r.db.Where("id = ? AND user_id = ?", id, userID).Limit(1).Find(&n)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	if n.ReadAt != nil {
+		return nil
+	}
+	return r.db.Model(&n).Update("read_at", time.Now()).Error
+}
+
+// MarkAllNotificationsRead returns how many notifications were marked
+func (r *GormRepo) MarkAllNotificationsRead(userID uint) (int64, error) {
+	res := r.db.Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// People and credits
+
+// GetPerson returns a person, nil if there's none
+func (r *GormRepo) GetPerson(id uint) (*domain.Person, error) {
+	var person domain.Person
+	if err := r.db.First(&person, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &person, nil
+}
+
+// ListPersonCredits returns a person's credits on stored movies with the
+// movies loaded, newest first. Deleted movies are left out.
+func (r *GormRepo) ListPersonCredits(personID uint) ([]domain.Credit, error) {
+	var credits []domain.Credit
+	err := r.db.Joins("JOIN movies ON movies.id = credits.movie_id AND movies.deleted_at IS NULL").
+		Preload("Movie.Genres").
+		Where("credits.person_id = ?", personID).
+		Order("movies.year DESC, movies.popularity DESC, credits.movie_id, credits.role, credits.billing_order, credits.id").
+		Find(&credits).Error
+	if err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+// setMovieCreditsTx replaces a movie's credits. Each credit's Person is
+// stored too, updating the name and photo of people already known.
+func setMovieCreditsTx(tx *gorm.DB, movieID uint, credits []domain.Credit) error {
+	if err := tx.Where("movie_id = ?", movieID).Delete(&domain.Credit{}).Error; err != nil {
+		return err
+	}
+	if len(credits) == 0 {
+		return nil
+	}
+	people := make([]domain.Person, 0, len(credits))
+	seen := make(map[uint]bool, len(credits))
+	rows := make([]domain.Credit, len(credits))
+	for i, c := range credits {
+		if c.Person != nil && !seen[c.Person.ID] {
+			seen[c.Person.ID] = true
+			people = append(people, *c.Person)
+		}
+		c.ID = 0
+		c.MovieID = movieID
+		c.Person = nil
+		c.Movie = nil
+		rows[i] = c
+	}
+	if len(people) > 0 {
+		// Lock people in ID order, so concurrent syncs of movies sharing cast
+		// can't deadlock
+		slices.SortFunc(people, func(a, b domain.Person) int { return cmp.Compare(a.ID, b.ID) })
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "profile_path", "known_for_department", "updated_at"}),
+		}).Create(&people).Error
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Create(&rows).Error
+}
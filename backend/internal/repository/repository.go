@@ -1,14 +1,56 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/HMZ-H/moviemate/internal/domain"
 )
 
+// ErrNotFound is returned by write operations whose target row doesn't exist
+var ErrNotFound = errors.New("record not found")
+
+// ErrExternalIDConflict is returned when a movie's TMDB and IMDb IDs belong
+// to two different stored movies
+var ErrExternalIDConflict = errors.New("external IDs match different movies")
+
+// ErrRetryable is returned when a transaction kept deadlocking or failing
+// to serialize against concurrent ones; trying again later should succeed
+var ErrRetryable = errors.New("transaction conflicted with a concurrent one")
+
 type MovieRepo interface {
 	CreateMovie(movie *domain.Movie) error
 	GetMovieByID(id uint) (*domain.Movie, error)
-	GetMovieByTitle(title string) (*domain.Movie, error)
-	ListMovies(limit, offset int) ([]domain.Movie, error)
+	FindMoviesByTitle(title string, year int) ([]domain.Movie, error) // year 0 matches any year
+	ListMovies(q domain.MovieListQuery) ([]domain.Movie, int64, error)
+	ListMoviesByGenre(genreIDs []uint, limit, offset int) ([]domain.Movie, error) // movies with all of genreIDs
+	UpsertMovie(movie *domain.Movie) (bool, error)                                // true when created
+	SearchMovies(q domain.MovieSearchQuery) ([]domain.MovieSearchHit, int64, error)
+	FuzzySearchMovies(q domain.MovieSearchQuery) ([]domain.MovieSearchHit, int64, error)
+	SuggestTitles(ctx context.Context, prefix string, limit int) ([]domain.TitleSuggestion, error)
+	SuggestPeople(ctx context.Context, prefix string, limit int) ([]domain.PersonSuggestion, error)
+}
+
+// MovieAdminRepo edits the catalog on behalf of an admin, recording each
+// change with the acting user
+type MovieAdminRepo interface {
+	GetMovieIncludingDeleted(id uint) (*domain.Movie, error)
+	AdminUpsertMovie(movie *domain.Movie, userID uint) (bool, error) // true when created
+	UpdateMovie(id uint, patch domain.MoviePatch, userID uint) (*domain.Movie, error)
+	DeleteMovie(id, userID uint) error // soft delete
+	RestoreMovie(id, userID uint) (*domain.Movie, error)
+	ListMovieAudits(movieID uint, limit int) ([]domain.MovieAudit, error) // newest first
+}
+
+type PersonRepo interface {
+	GetPerson(id uint) (*domain.Person, error)
+	ListPersonCredits(personID uint) ([]domain.Credit, error) // with movies, newest first
+}
+
+type GenreRepo interface {
+	ListGenres(mediaType string) ([]domain.Genre, error) // "" for all genres
+	UpsertGenres(genres []domain.Genre) error
 }
 
 type UserRepo interface {
@@ -16,20 +58,125 @@ type UserRepo interface {
 	GetByID(id uint) (*domain.User, error)
 	GetByUsername(username string) (*domain.User, error)
 	GetByEmail(email string) (*domain.User, error)
+	UpdateProfile(id uint, patch domain.ProfilePatch) error
+	GetUserRole(id uint) (string, error) // "" if the user doesn't exist
+	GetUsername(id uint) (string, error) // "" if the user doesn't exist
+	SetUserRole(id uint, role string) error
 }
 
 // Combined repository interface
 type Repository interface {
 	UserRepo
 	MovieRepo
+	MovieAdminRepo
+	PersonRepo
+	GenreRepo
 	WatchlistRepo
+	MetadataRepo
+	DiaryRepo
+	ListRepo
+	NotificationRepo
+	AvailabilityRepo
+	TVRepo
+	CacheRepo
+	CatalogSyncRepo
 }
 
 type WatchlistRepo interface {
-	AddWatchlist(item *domain.WatchlistItem) error
-	RemoveWatchlist(userID, movieID uint) error
-	ListWatchlistByUser(userID uint) ([]domain.Movie, error) // returns movie objects
-	ListWatchlistIDsByUser(userID uint) ([]uint, error)      // returns TMDB movie IDs
+	AddWatchlist(item *domain.WatchlistItem) (bool, error)           // false if already saved
+	RemoveWatchlist(list domain.ListRef, movieID uint) (bool, error) // false if not saved
+	RestoreWatchlist(list domain.ListRef, movieIDs []uint, removedSince time.Time) ([]domain.BulkResult, error)
+	PurgeWatchlistTombstones(removedBefore time.Time) (int64, error)
+	BulkWatchlist(list domain.ListRef, addedBy uint, action string, movieIDs []uint) ([]domain.BulkResult, error)
+	ImportWatchlist(userID uint, items []domain.WatchlistItem) (int, error)                         // personal list; keeps each item's AddedAt
+	ListWatchlistByUser(userID uint, filter domain.WatchlistFilter) ([]domain.WatchlistItem, error) // personal list rows with tags
+	ListWatchlistIDs(list domain.ListRef) ([]uint, error)                                           // returns TMDB movie IDs
+	ListWatchlistIDsNeedingMetadata(list domain.ListRef, staleBefore time.Time) ([]uint, error)
+	ListWatchlistIDsNeedingOffers(list domain.ListRef, staleBefore time.Time) ([]uint, error)
+	ListWatchlistPage(query domain.WatchlistQuery) (*domain.WatchlistPage, error)
+	ListWatchlistTags(list domain.ListRef) ([]domain.TagCount, error)
+	UpdateWatchlistItem(list domain.ListRef, movieID uint, patch domain.WatchlistItemPatch) error
+	MoveWatchlistItem(list domain.ListRef, movieID, afterID uint) error // afterID 0 moves to the top
+	GetWatchlistItems(list domain.ListRef, movieIDs []uint) ([]domain.WatchlistItem, error)
+	GetWatchlistVersion(list domain.ListRef) (int64, error)
+	GetWatchlistMetadataStamp(list domain.ListRef) (hydrated int64, latest time.Time, err error)
+	ListWatchlistChanges(list domain.ListRef, since int64, limit int) ([]domain.WatchlistChange, error) // oldest first
+	ListPersonalWatchlists(userIDs []uint) ([]domain.WatchlistItem, error)                              // without tags
 	GetUserByID(id uint) (*domain.User, error)
 	CreateUser(user *domain.User) error
 }
+
+type MetadataRepo interface {
+	GetMetadataByIDs(ids []uint) ([]domain.MovieMetadata, error)
+	UpsertMetadata(m *domain.MovieMetadata) error
+}
+
+type DiaryRepo interface {
+	AddDiaryEntries(entries []domain.DiaryEntry) (int, error) // returns number inserted
+	ListWatchedIDs(userIDs []uint) ([]uint, error)            // titles any of the users has logged
+}
+
+type ListRepo interface {
+	CreateList(list *domain.Watchlist) error
+	GetList(id uint) (*domain.Watchlist, error) // includes members
+	ListsForUser(userID uint) ([]domain.ListAccess, error)
+	RenameList(id uint, name string) error
+	SetListSharing(id uint, visibility string, slug *string, shareNotes bool) error
+	GetPersonalSharing(userID uint) (*domain.Watchlist, error) // personal list as ID 0
+	SetPersonalSharing(userID uint, visibility string, slug *string, shareNotes bool) error
+	GetPublicList(slug string) (*domain.Watchlist, error) // nil unless the list is public; ID 0 for a personal list
+	DeleteList(id uint) error
+	GetListRole(listID, userID uint) (*domain.Watchlist, string, error) // empty role if no access
+	SetMemberRole(listID, userID uint, role string) error
+	RemoveMember(listID, userID uint) (bool, error)
+	CreateInvite(invite *domain.WatchlistInvite) error
+	AcceptInvite(tokenHash string, userID uint) (*domain.ListAccess, error)
+}
+
+type NotificationRepo interface {
+	ListReminderCandidates(minYear int) ([]domain.ReminderCandidate, error)
+	GetReleaseDates(ids []uint) ([]domain.ReleaseDate, error)
+	ReplaceReleaseDates(tmdbID uint, dates []domain.ReleaseDate) error
+	CreateNotification(n *domain.Notification) (bool, error) // false if already sent
+	MarkNotificationEmailed(id uint) error
+	ListPendingEmails(createdSince time.Time) ([]domain.PendingEmail, error)
+	ListNotifications(userID uint, unreadOnly bool, limit int) ([]domain.Notification, error)
+	CountUnreadNotifications(userID uint) (int64, error)
+	MarkNotificationRead(userID, id uint) error
+	MarkAllNotificationsRead(userID uint) (int64, error)
+}
+
+type AvailabilityRepo interface {
+	GetWatchOffers(ids []uint, region string) ([]domain.WatchOffer, error)
+	GetWatchOffersFetchedAt(ids []uint) (map[uint]time.Time, error)
+	ReplaceWatchOffers(tmdbID uint, offers []domain.WatchOffer) error // all regions
+	GetUserRegion(userID uint) (string, error)
+	GetUserServices(userID uint) ([]int, error)
+	SetUserServices(userID uint, providerIDs []int) error
+}
+
+type TVRepo interface {
+	GetTVShow(showID uint) (*domain.TVShow, error)
+	GetTVSeasons(showID uint) ([]domain.TVSeason, error)
+	ReplaceTVShow(show *domain.TVShow, seasons []domain.TVSeason) error
+	GetTVEpisodes(showID uint, season int) ([]domain.TVEpisode, error)
+	ReplaceTVEpisodes(showID uint, season int, episodes []domain.TVEpisode) error
+	ListEpisodeProgress(userID, showID uint) ([]domain.EpisodeProgress, error)
+	SetEpisodesWatched(userID, showID uint, season int, episodes []int, watched bool) (int64, error)
+	ListShowActivity(userID uint, limit int) ([]domain.ShowActivity, error)
+}
+
+type CacheRepo interface {
+	GetCache(key string) ([]byte, bool, error) // false when missing or expired
+	SetCache(key string, value []byte, ttl time.Duration) error
+	PurgeExpiredCache(before time.Time) (int64, error)
+}
+
+type CatalogSyncRepo interface {
+	GetLatestSyncRun(exportFile string) (*domain.CatalogSyncRun, error) // nil if the file was never synced
+	SaveSyncRun(run *domain.CatalogSyncRun) error
+	UpsertSyncItems(items []domain.CatalogSyncItem) error
+	ListSyncItemsToFetch(staleBefore time.Time, limit int) ([]domain.CatalogSyncItem, error)
+	MarkSyncItemFetched(tmdbID uint, at time.Time, errMsg string) error
+	UpsertMovie(movie *domain.Movie) (bool, error)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// txAttempts is how many times retryTx runs a transaction that deadlocks
+const txAttempts = 3
+
+// errInsertRaced marks a unique violation on an insert made because no
+// existing row was found: a concurrent transaction stored the same row
+// first, and running again finds and updates it
+var errInsertRaced = errors.New("row was inserted concurrently")
+
+// retryTx runs fn again when Postgres aborted it for a deadlock or
+// serialization failure, or it lost an insert race (errInsertRaced), returning
+// ErrRetryable if it never got through
+func retryTx(fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= txAttempts; attempt++ {
+		if err = fn(); err == nil || !isTxConflict(err) {
+			return err
+		}
+		time.Sleep(time.Duration(attempt) * 50 * time.Millisecond)
+	}
+	return fmt.Errorf("%w: %v", ErrRetryable, err)
+}
+
+// isTxConflict reports whether err is a deadlock, serialization failure or
+// lost insert race
+func isTxConflict(err error) bool {
+	if errors.Is(err, errInsertRaced) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40P01" || pgErr.Code == "40001"
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TV season cache
+
+func (r *GormRepo) GetTVShow(showID uint) (*domain.TVShow, error) {
+	var show domain.TVShow
+	if err := r.db.First(&show, showID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &show, nil
+}
+
+// GetTVSeasons returns a show's cached seasons in order
+func (r *GormRepo) GetTVSeasons(showID uint) ([]domain.TVSeason, error) {
+	var seasons []domain.TVSeason
+	if err := r.db.Where("show_id = ?", showID).Order("season_number").Find(&seasons).Error; err != nil {
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// ReplaceTVShow stores a show and its season list. Seasons that still exist
+// keep their cached episodes; seasons TMDB dropped are deleted with theirs.
+func (r *GormRepo) ReplaceTVShow(show *domain.TVShow, seasons []domain.TVSeason) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(show).Error; err != nil {
+			return err
+		}
+		numbers := make([]int, len(seasons))
+		for i, se := range seasons {
+			numbers[i] = se.SeasonNumber
+		}
+		stale, args := "show_id = ?", []interface{}{show.TMDBID}
+		if len(numbers) > 0 {
+			stale += " AND season_number NOT IN ?"
+			args = append(args, numbers)
+		}
+		if err := tx.Where(stale, args...).Delete(&domain.TVEpisode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where(stale, args...).Delete(&domain.TVSeason{}).Error; err != nil {
+			return err
+		}
+		if len(seasons) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "show_id"}, {Name: "season_number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "episode_count", "air_date"}),
+		}).Create(&seasons).Error
+	})
+}
+
+// GetTVEpisodes returns a season's cached episodes in order
+func (r *GormRepo) GetTVEpisodes(showID uint, season int) ([]domain.TVEpisode, error) {
+	var episodes []domain.TVEpisode
+	if err := r.db.Where("show_id = ? AND season_number = ?", showID, season).
+		Order("episode_number").
+		Find(&episodes).Error; err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
+
+// ReplaceTVEpisodes stores a season's episodes and marks them fetched
+func (r *GormRepo) ReplaceTVEpisodes(showID uint, season int, episodes []domain.TVEpisode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("show_id = ? AND season_number = ?", showID, season).Delete(&domain.TVEpisode{}).Error; err != nil {
+			return err
+		}
+		if len(episodes) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&episodes, 200).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&domain.TVSeason{}).
+			Where("show_id = ? AND season_number = ?", showID, season).
+			Update("episodes_fetched_at", time.Now()).Error
+	})
+}
+
+// Episode progress
+
+func (r *GormRepo) ListEpisodeProgress(userID, showID uint) ([]domain.EpisodeProgress, error) {
+	var progress []domain.EpisodeProgress
+	if err := r.db.Where("user_id = ? AND show_id = ?", userID, showID).
+		Order("season_number, episode_number").
+		Find(&progress).Error; err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// SetEpisodesWatched marks episodes of one season watched or unwatched and
+// returns how many changed
+func (r *GormRepo) SetEpisodesWatched(userID, showID uint, season int, episodes []int, watched bool) (int64, error) {
+	if len(episodes) == 0 {
+		return 0, nil
+	}
+	if !watched {
+		res := r.db.Where("user_id = ? AND show_id = ? AND season_number = ? AND episode_number IN ?", userID, showID, season, episodes).
+			Delete(&domain.EpisodeProgress{})
+		return res.RowsAffected, res.Error
+	}
+	now := time.Now()
+	rows := make([]domain.EpisodeProgress, len(episodes))
+	for i, ep := range episodes {
+		rows[i] = domain.EpisodeProgress{UserID: userID, ShowID: showID, SeasonNumber: season, EpisodeNumber: ep, WatchedAt: now}
+	}
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+	return res.RowsAffected, res.Error
+}
+
+// ListShowActivity returns the shows the user has watched episodes of, most
+// recently watched first
+func (r *GormRepo) ListShowActivity(userID uint, limit int) ([]domain.ShowActivity, error) {
+	var activity []domain.ShowActivity
+	if err := r.db.Model(&domain.EpisodeProgress{}).
+		Select("show_id, COUNT(*) AS watched, MAX(watched_at) AS last_watched_at").
+		Where("user_id = ?", userID).
+		Group("show_id").
+		Order("last_watched_at DESC").
+		Limit(limit).
+		Scan(&activity).Error; err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+)
+
+// recordChangeTx bumps the list's version and logs the change under it. The
+// upsert locks the list's version row until the transaction ends, so versions
+// become visible in the order they were handed out.
+func recordChangeTx(tx *gorm.DB, list domain.ListRef, movieID uint, kind string) error {
+	var version int64
+	if err := tx.Raw(`INSERT INTO watchlist_versions (user_id, list_id, version) VALUES (?, ?, 1)
+		ON CONFLICT (user_id, list_id) DO UPDATE SET version = watchlist_versions.version + 1
+		RETURNING version`, list.OwnerID, list.ListID).Scan(&version).Error; err != nil {
+		return err
+	}
+	return tx.Create(&domain.WatchlistChange{
+		UserID:  list.OwnerID,
+		ListID:  list.ListID,
+		Version: version,
+		MovieID: movieID,
+		Kind:    kind,
+	}).Error
+}
+
+// GetWatchlistVersion returns the list's latest change version, 0 if it has never changed
+func (r *GormRepo) GetWatchlistVersion(list domain.ListRef) (int64, error) {
+	var versions []int64
+	if err := r.db.Model(&domain.WatchlistVersion{}).
+		Where("user_id = ? AND list_id = ?", list.OwnerID, list.ListID).
+		Pluck("version", &versions).Error; err != nil {
+		return 0, err
+	}
+	if len(versions) == 0 {
+		return 0, nil
+	}
+	return versions[0], nil
+}
+
+// GetWatchlistMetadataStamp fingerprints the metadata cached for the list's
+// titles: how many have metadata and when the newest was fetched. Metadata
+// refreshes without bumping the list version, so cached responses that
+// include it need this too.
+func (r *GormRepo) GetWatchlistMetadataStamp(list domain.ListRef) (int64, time.Time, error) {
+	var row struct {
+		Hydrated int64
+		Latest   *time.Time
+	}
+	if err := r.db.Model(&domain.WatchlistItem{}).
+		Select("COUNT(movie_metadata.tmdb_id) AS hydrated, MAX(movie_metadata.fetched_at) AS latest").
+		Joins("LEFT JOIN movie_metadata ON movie_metadata.tmdb_id = watchlist_items.movie_id").
+		Scopes(inList(list)).
+		Scan(&row).Error; err != nil {
+		return 0, time.Time{}, err
+	}
+	if row.Latest == nil {
+		return row.Hydrated, time.Time{}, nil
+	}
+	return row.Hydrated, *row.Latest, nil
+}
+
+// ListWatchlistChanges returns up to limit changes after version since, oldest first
+func (r *GormRepo) ListWatchlistChanges(list domain.ListRef, since int64, limit int) ([]domain.WatchlistChange, error) {
+	var changes []domain.WatchlistChange
+	if err := r.db.Where("user_id = ? AND list_id = ? AND version > ?", list.OwnerID, list.ListID, since).
+		Order("version").
+		Limit(limit).
+		Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetWatchlistItems returns the list's rows for the given titles, with tags
+func (r *GormRepo) GetWatchlistItems(list domain.ListRef, movieIDs []uint) ([]domain.WatchlistItem, error) {
+	var items []domain.WatchlistItem
+	if len(movieIDs) == 0 {
+		return items, nil
+	}
+	if err := r.db.Scopes(inList(list)).
+		Where("movie_id IN ?", movieIDs).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
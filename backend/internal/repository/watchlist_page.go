@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor is returned when a page cursor can't be decoded or was
+// issued for a different sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// watchlistSort describes how a sort key maps onto SQL
+type watchlistSort struct {
+	expr        string // key expression over watchlist_items joined with movie_metadata
+	defaultDesc bool
+	idDesc      bool // tie-break direction relative to an ascending key
+}
+
+var watchlistSorts = map[string]watchlistSort{
+	domain.SortRank:   {expr: "watchlist_items.rank", idDesc: true},
+	domain.SortAdded:  {expr: "watchlist_items.added_at", defaultDesc: true},
+	domain.SortTitle:  {expr: "LOWER(COALESCE(movie_metadata.title, ''))"},
+	domain.SortYear:   {expr: "COALESCE(movie_metadata.release_year, 0)", defaultDesc: true},
+	domain.SortRating: {expr: "COALESCE(movie_metadata.rating, 0)", defaultDesc: true},
+}
+
+// ValidWatchlistSort reports whether sort is a supported sort key
+func ValidWatchlistSort(sort string) bool {
+	_, ok := watchlistSorts[sort]
+	return ok
+}
+
+// DefaultWatchlistSortDesc reports the natural direction of a sort key
+func DefaultWatchlistSortDesc(sort string) bool {
+	return watchlistSorts[sort].defaultDesc
+}
+
+// watchlistCursor is the decoded form of WatchlistPage.NextCursor
+type watchlistCursor struct {
+	Sort string          `json:"s"`
+	Desc bool            `json:"d"`
+	Key  json.RawMessage `json:"k"`
+	ID   uint            `json:"id"`
+}
+
+// watchlistScope selects a list's items joined with their cached metadata
+func (r *GormRepo) watchlistScope(list domain.ListRef, f domain.WatchlistFilter) *gorm.DB {
+	q := r.db.Model(&domain.WatchlistItem{}).
+		Joins("LEFT JOIN movie_metadata ON movie_metadata.tmdb_id = watchlist_items.movie_id").
+		Scopes(inList(list))
+	if len(f.Tags) > 0 {
+		q = q.Where("watchlist_items.id IN (?)", r.db.Model(&domain.WatchlistTag{}).
+			Select("watchlist_item_id").
+			Where("user_id = ? AND name IN ?", list.OwnerID, f.Tags).
+			Group("watchlist_item_id").
+			Having("COUNT(DISTINCT name) = ?", len(f.Tags)))
+	}
+	if f.Genre != "" {
+		q = q.Where("LOWER(?) = ANY(string_to_array(LOWER(movie_metadata.genres), ','))", f.Genre)
+	}
+	if f.MediaType != "" {
+		q = q.Where("movie_metadata.media_type = ?", f.MediaType)
+	}
+	if f.YearFrom > 0 {
+		q = q.Where("movie_metadata.release_year >= ?", f.YearFrom)
+	}
+	if f.YearTo > 0 {
+		q = q.Where("movie_metadata.release_year <= ?", f.YearTo)
+	}
+	if f.RuntimeMin > 0 {
+		q = q.Where("movie_metadata.runtime >= ?", f.RuntimeMin)
+	}
+	if f.RuntimeMax > 0 {
+		q = q.Where("movie_metadata.runtime <= ?", f.RuntimeMax)
+	}
+	if len(f.ProviderIDs) > 0 {
+		q = q.Where("watchlist_items.movie_id IN (?)", r.db.Model(&domain.WatchOffer{}).
+			Select("tmdb_id").
+			Where("region = ? AND provider_id IN ? AND type IN ?", f.Region, f.ProviderIDs, domain.SubscriptionOfferTypes))
+	}
+	return q
+}
+
+// ListWatchlistPage returns one keyset-paginated page of a watchlist
+func (r *GormRepo) ListWatchlistPage(query domain.WatchlistQuery) (*domain.WatchlistPage, error) {
+	sort, ok := watchlistSorts[query.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort %q", query.Sort)
+	}
+
+	var page domain.WatchlistPage
+	if err := r.watchlistScope(query.List, query.Filter).Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	keyDir, keyCmp := "ASC", ">"
+	if query.Desc {
+		keyDir, keyCmp = "DESC", "<"
+	}
+	idDesc := sort.idDesc != query.Desc
+	idDir, idCmp := "ASC", ">"
+	if idDesc {
+		idDir, idCmp = "DESC", "<"
+	}
+
+	q := r.watchlistScope(query.List, query.Filter)
+	if query.Cursor != "" {
+		cur, key, err := decodeWatchlistCursor(query)
+		if err != nil {
+			return nil, err
+		}
+		q = q.Where(fmt.Sprintf("((%s %s ?) OR (%s = ? AND watchlist_items.id %s ?))", sort.expr, keyCmp, sort.expr, idCmp),
+			key, key, cur.ID)
+	}
+
+	var rows []watchlistPageRow
+	if err := q.Select(fmt.Sprintf("watchlist_items.*, %s AS sort_key", sort.expr)).
+		Order(fmt.Sprintf("%s %s, watchlist_items.id %s", sort.expr, keyDir, idDir)).
+		Limit(query.Limit + 1).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	if len(rows) > query.Limit {
+		rows = rows[:query.Limit]
+		cursor, err := encodeWatchlistCursor(query, rows[len(rows)-1])
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = cursor
+	}
+	page.Items = make([]domain.WatchlistItem, len(rows))
+	for i, row := range rows {
+		page.Items[i] = row.WatchlistItem
+	}
+	if err := r.loadTags(page.Items); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// watchlistPageRow is a page item with the value it was sorted by, so the
+// cursor comes from the same snapshot as the page
+type watchlistPageRow struct {
+	domain.WatchlistItem
+	SortKey sortKey `gorm:"column:sort_key"`
+}
+
+// sortKey holds a sort expression's value as the driver returned it
+type sortKey struct{ v interface{} }
+
+func (k *sortKey) Scan(src interface{}) error {
+	k.v = src
+	return nil
+}
+
+func (k sortKey) Value() (driver.Value, error) { return k.v, nil }
+
+// GormDataType lets GORM treat the key as a plain column; it's never migrated
+func (sortKey) GormDataType() string { return "sort_key" }
+
+// loadTags fills in the tags of items, ordered by name
+func (r *GormRepo) loadTags(items []domain.WatchlistItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	var tags []domain.WatchlistTag
+	if err := r.db.Where("watchlist_item_id IN ?", ids).Order("name").Find(&tags).Error; err != nil {
+		return err
+	}
+	byItem := make(map[uint][]domain.WatchlistTag, len(items))
+	for _, tag := range tags {
+		byItem[tag.WatchlistItemID] = append(byItem[tag.WatchlistItemID], tag)
+	}
+	for i := range items {
+		items[i].Tags = byItem[items[i].ID]
+	}
+	return nil
+}
+
+// encodeWatchlistCursor captures the sort key and id of a page's last row
+func encodeWatchlistCursor(query domain.WatchlistQuery, last watchlistPageRow) (string, error) {
+	var key interface{}
+	switch v := last.SortKey.v.(type) {
+	case time.Time:
+		key = v.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		key = string(v)
+	case string, int64, float64:
+		key = v
+	default:
+		return "", fmt.Errorf("unexpected %T sort key for %q", v, query.Sort)
+	}
+	if query.Sort == domain.SortYear {
+		// Keys are decoded by sort, so keep a year integral and a rank or
+		// rating fractional whatever type the driver chose
+		if f, ok := key.(float64); ok {
+			key = int64(f)
+		}
+	} else if n, ok := key.(int64); ok {
+		key = float64(n)
+	}
+
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(watchlistCursor{Sort: query.Sort, Desc: query.Desc, Key: raw, ID: last.ID})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeWatchlistCursor parses a cursor and returns its key as a SQL argument
+func decodeWatchlistCursor(query domain.WatchlistQuery) (*watchlistCursor, interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+	if err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+	var cur watchlistCursor
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+	if cur.Sort != query.Sort || cur.Desc != query.Desc {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	switch query.Sort {
+	case domain.SortAdded:
+		var s string
+		if err := json.Unmarshal(cur.Key, &s); err != nil {
+			return nil, nil, ErrInvalidCursor
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, nil, ErrInvalidCursor
+		}
+		return &cur, t, nil
+	case domain.SortTitle:
+		var s string
+		if err := json.Unmarshal(cur.Key, &s); err != nil {
+			return nil, nil, ErrInvalidCursor
+		}
+		return &cur, s, nil
+	case domain.SortYear:
+		var v int64
+		if err := json.Unmarshal(cur.Key, &v); err != nil {
+			return nil, nil, ErrInvalidCursor
+		}
+		return &cur, v, nil
+	default:
+		var v float64
+		if err := json.Unmarshal(cur.Key, &v); err != nil {
+			return nil, nil, ErrInvalidCursor
+		}
+		return &cur, v, nil
+	}
+}
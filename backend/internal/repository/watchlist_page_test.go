@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+func TestWatchlistCursorRoundTrip(t *testing.T) {
+	added := time.Date(2024, 3, 1, 12, 30, 0, 123456000, time.FixedZone("CET", 3600))
+	tests := []struct {
+		sort string
+		key  interface{} // as the driver scans the sort_key column
+		want interface{}
+	}{
+		{domain.SortRank, 1.5, 1.5},
+		{domain.SortRank, int64(2), 2.0},
+		{domain.SortAdded, added, added.UTC()},
+		{domain.SortTitle, []byte("inception"), "inception"},
+		{domain.SortYear, int64(2010), int64(2010)},
+		{domain.SortRating, 8.4, 8.4},
+	}
+	for _, tt := range tests {
+		query := domain.WatchlistQuery{Sort: tt.sort, Desc: true}
+		row := watchlistPageRow{WatchlistItem: domain.WatchlistItem{ID: 42}, SortKey: sortKey{tt.key}}
+		cursor, err := encodeWatchlistCursor(query, row)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sort, err)
+		}
+		query.Cursor = cursor
+		cur, key, err := decodeWatchlistCursor(query)
+		if err != nil {
+			t.Fatalf("%s: decode: %v", tt.sort, err)
+		}
+		if cur.ID != 42 {
+			t.Errorf("%s: cursor id = %d, want 42", tt.sort, cur.ID)
+		}
+		if tm, ok := key.(time.Time); ok {
+			if !tm.Equal(tt.want.(time.Time)) {
+				t.Errorf("%s: key = %v, want %v", tt.sort, tm, tt.want)
+			}
+		} else if key != tt.want {
+			t.Errorf("%s: key = %#v, want %#v", tt.sort, key, tt.want)
+		}
+	}
+
+	// A cursor from one sort can't be replayed against another
+	cursor, err := encodeWatchlistCursor(domain.WatchlistQuery{Sort: domain.SortRank},
+		watchlistPageRow{SortKey: sortKey{1.0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := decodeWatchlistCursor(domain.WatchlistQuery{Sort: domain.SortTitle, Cursor: cursor}); err != ErrInvalidCursor {
+		t.Errorf("decode with another sort: err = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// availabilityTTL is how long cached watch offers are served; catalogues
+// change monthly but titles move between services at any time
+const availabilityTTL = 24 * time.Hour
+
+// availabilityErrorTTL is how long a title whose fetch failed isn't asked
+// for again, so an outage doesn't cost a call per title on every request
+const availabilityErrorTTL = 5 * time.Minute
+
+// AvailabilityCache serves where titles can be watched from the local table,
+// fetching titles from the provider when they are missing or stale
+type AvailabilityCache struct {
+	repo     repository.AvailabilityRepo
+	provider domain.WatchProviderService
+
+	mu     sync.Mutex
+	failed map[uint]time.Time // IDs whose fetch failed and when to try them again
+}
+
+// NewAvailabilityCache creates a cache. provider may be nil, in which case
+// only already cached offers are returned.
+func NewAvailabilityCache(repo repository.AvailabilityRepo, provider domain.WatchProviderService) *AvailabilityCache {
+	return &AvailabilityCache{repo: repo, provider: provider, failed: map[uint]time.Time{}}
+}
+
+// StaleBefore returns the fetch time before which cached offers are refreshed
+func (ac *AvailabilityCache) StaleBefore() time.Time {
+	return time.Now().Add(-availabilityTTL)
+}
+
+// Lookup returns offers in region keyed by TMDB ID for the titles in meta,
+// whose media types say which TMDB endpoint to ask. A fetch that fails keeps
+// whatever was cached before and isn't retried for availabilityErrorTTL.
+func (ac *AvailabilityCache) Lookup(meta map[uint]domain.MovieMetadata, region string) (map[uint][]domain.WatchOffer, error) {
+	ids := make([]uint, 0, len(meta))
+	for id := range meta {
+		ids = append(ids, id)
+	}
+	if ac.provider != nil {
+		if err := ac.refresh(meta, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	offers, err := ac.repo.GetWatchOffers(ids, region)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint][]domain.WatchOffer, len(ids))
+	for _, o := range offers {
+		result[o.TMDBID] = append(result[o.TMDBID], o)
+	}
+	return result, nil
+}
+
+// refresh fetches every title not fetched within availabilityTTL, skipping
+// recent failures
+func (ac *AvailabilityCache) refresh(meta map[uint]domain.MovieMetadata, ids []uint) error {
+	fetched, err := ac.repo.GetWatchOffersFetchedAt(ids)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ac.mu.Lock()
+	for id, retryAt := range ac.failed {
+		if now.After(retryAt) {
+			delete(ac.failed, id)
+		}
+	}
+	var stale []uint
+	for _, id := range ids {
+		if at, ok := fetched[id]; ok && now.Sub(at) <= availabilityTTL {
+			continue
+		}
+		if _, failed := ac.failed[id]; failed || meta[id].MediaType == "" {
+			continue
+		}
+		stale = append(stale, id)
+	}
+	ac.mu.Unlock()
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, id := range stale {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id uint, mediaType string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			offers, err := ac.provider.FetchWatchOffers(id, mediaType)
+			if err != nil {
+				log.Printf("watch providers fetch %d: %v", id, err)
+				ac.mu.Lock()
+				ac.failed[id] = time.Now().Add(availabilityErrorTTL)
+				ac.mu.Unlock()
+				return
+			}
+			if err := ac.repo.ReplaceWatchOffers(id, offers); err != nil {
+				log.Printf("watch providers store %d: %v", id, err)
+			}
+		}(id, meta[id].MediaType)
+	}
+	wg.Wait()
+	return nil
+}
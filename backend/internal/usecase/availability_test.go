@@ -0,0 +1,159 @@
+package usecase_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/infra"
+	"github.com/HMZ-H/moviemate/internal/usecase"
+)
+
+// memAvailabilityRepo is an in-memory repository.AvailabilityRepo
+type memAvailabilityRepo struct {
+	mu      sync.Mutex
+	offers  map[uint][]domain.WatchOffer
+	fetched map[uint]time.Time
+}
+
+func newMemAvailabilityRepo() *memAvailabilityRepo {
+	return &memAvailabilityRepo{offers: map[uint][]domain.WatchOffer{}, fetched: map[uint]time.Time{}}
+}
+
+func (r *memAvailabilityRepo) GetWatchOffers(ids []uint, region string) ([]domain.WatchOffer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.WatchOffer
+	for _, id := range ids {
+		for _, o := range r.offers[id] {
+			if o.Region == region {
+				out = append(out, o)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r *memAvailabilityRepo) GetWatchOffersFetchedAt(ids []uint) (map[uint]time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := map[uint]time.Time{}
+	for _, id := range ids {
+		if at, ok := r.fetched[id]; ok {
+			out[id] = at
+		}
+	}
+	return out, nil
+}
+
+func (r *memAvailabilityRepo) ReplaceWatchOffers(tmdbID uint, offers []domain.WatchOffer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.offers[tmdbID] = offers
+	r.fetched[tmdbID] = time.Now()
+	return nil
+}
+
+func (r *memAvailabilityRepo) GetUserRegion(uint) (string, error)  { return "US", nil }
+func (r *memAvailabilityRepo) GetUserServices(uint) ([]int, error) { return nil, nil }
+func (r *memAvailabilityRepo) SetUserServices(uint, []int) error   { return nil }
+
+// countingProvider counts calls to the provider it wraps
+type countingProvider struct {
+	mu    sync.Mutex
+	calls int
+	next  domain.WatchProviderService
+	err   error
+}
+
+func (p *countingProvider) FetchWatchOffers(tmdbID uint, mediaType string) ([]domain.WatchOffer, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.next.FetchWatchOffers(tmdbID, mediaType)
+}
+
+func TestAvailabilityCacheFromFixture(t *testing.T) {
+	fixture, err := infra.NewFixtureWatchProviderService("../infra/testdata/watch_providers.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &countingProvider{next: fixture}
+	cache := usecase.NewAvailabilityCache(newMemAvailabilityRepo(), provider)
+	meta := map[uint]domain.MovieMetadata{
+		27205: {TMDBID: 27205, MediaType: "movie"},
+		1396:  {TMDBID: 1396, MediaType: "tv"},
+		999:   {TMDBID: 999, MediaType: "movie"}, // not in the fixture
+	}
+
+	offers, err := cache.Lookup(meta, "US")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inception := offers[27205]
+	if len(inception) != 4 {
+		t.Fatalf("Inception US offers = %d, want 4: %+v", len(inception), inception)
+	}
+	var netflix bool
+	for _, o := range inception {
+		if o.ProviderID == 8 && o.Type == domain.OfferStream {
+			netflix = true
+		}
+	}
+	if !netflix {
+		t.Errorf("Inception US offers miss Netflix streaming: %+v", inception)
+	}
+	if got := len(offers[1396]); got != 2 {
+		t.Errorf("Breaking Bad US offers = %d, want 2", got)
+	}
+	if got := len(offers[999]); got != 0 {
+		t.Errorf("unknown title has %d offers, want none", got)
+	}
+
+	gb, err := cache.Lookup(meta, "GB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(gb[27205]); got != 1 || gb[27205][0].ProviderID != 9 {
+		t.Errorf("Inception GB offers = %+v, want Amazon Prime Video only", gb[27205])
+	}
+	// Every title, including the one with no offers, was cached on the first lookup
+	if provider.calls != 3 {
+		t.Errorf("provider calls = %d, want 3", provider.calls)
+	}
+}
+
+func TestAvailabilityCacheBacksOffAfterError(t *testing.T) {
+	provider := &countingProvider{err: errors.New("TMDB is down")}
+	cache := usecase.NewAvailabilityCache(newMemAvailabilityRepo(), provider)
+	meta := map[uint]domain.MovieMetadata{27205: {TMDBID: 27205, MediaType: "movie"}}
+
+	for i := 0; i < 3; i++ {
+		offers, err := cache.Lookup(meta, "US")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(offers[27205]) != 0 {
+			t.Fatalf("offers after a failed fetch = %+v, want none", offers[27205])
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider calls = %d, want 1 while the failure is remembered", provider.calls)
+	}
+}
+
+func TestAvailabilityCacheSkipsUnknownMediaType(t *testing.T) {
+	provider := &countingProvider{err: errors.New("should not be called")}
+	cache := usecase.NewAvailabilityCache(newMemAvailabilityRepo(), provider)
+	if _, err := cache.Lookup(map[uint]domain.MovieMetadata{1: {TMDBID: 1}}, "US"); err != nil {
+		t.Fatal(err)
+	}
+	if provider.calls != 0 {
+		t.Errorf("provider calls = %d, want 0 for a title without a media type", provider.calls)
+	}
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// CachePurger deletes expired rows from the shared cache table, which are
+// otherwise only skipped on read
+type CachePurger struct {
+	repo repository.CacheRepo
+}
+
+func NewCachePurger(repo repository.CacheRepo) *CachePurger {
+	return &CachePurger{repo: repo}
+}
+
+// Start purges now and then every interval until ctx is cancelled
+func (cp *CachePurger) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if n, err := cp.repo.PurgeExpiredCache(time.Now()); err != nil {
+			log.Printf("cache purge: %v", err)
+		} else if n > 0 {
+			log.Printf("cache purge: deleted %d expired entries", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
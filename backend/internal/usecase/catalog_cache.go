@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+// catalogTTL is how long a catalog response is served as fresh, and for how
+// much longer it may be served stale while it is refreshed in the background
+type catalogTTL struct {
+	fresh, stale time.Duration
+}
+
+// Per-endpoint TTLs: listings move during the day, title details rarely do
+var catalogTTLs = map[string]catalogTTL{
+	"trending": {fresh: time.Hour, stale: 6 * time.Hour},
+	"list":     {fresh: time.Hour, stale: 6 * time.Hour},
+	"discover": {fresh: time.Hour, stale: 6 * time.Hour},
+	"search":   {fresh: 15 * time.Minute, stale: time.Hour},
+	"details":  {fresh: 24 * time.Hour, stale: 7 * 24 * time.Hour},
+	"videos":   {fresh: 24 * time.Hour, stale: 7 * 24 * time.Hour},
+	"images":   {fresh: 24 * time.Hour, stale: 7 * 24 * time.Hour},
+	"genres":   {fresh: 7 * 24 * time.Hour, stale: 30 * 24 * time.Hour},
+}
+
+// sharedCachePrefix namespaces catalog keys in the shared store
+const sharedCachePrefix = "catalog:"
+
+// CachedCatalog puts a cache in front of a catalog provider. Responses are
+// kept in an in-process LRU and, when a shared store is configured, in that
+// store so other instances can reuse them. Concurrent misses for the same
+// request share one upstream call, and stale responses are served while a
+// background refresh runs. Returned values are shared; callers must not
+// modify them.
+type CachedCatalog struct {
+	next   domain.CatalogProvider
+	shared domain.CacheStore
+	local  *lruCache
+	group  singleflight.Group
+	stats  map[string]*endpointStats
+}
+
+// NewCachedCatalog wraps next. shared may be nil to cache in memory only;
+// size is the maximum number of responses kept in memory.
+func NewCachedCatalog(next domain.CatalogProvider, shared domain.CacheStore, size int) *CachedCatalog {
+	stats := make(map[string]*endpointStats, len(catalogTTLs))
+	for endpoint := range catalogTTLs {
+		stats[endpoint] = &endpointStats{}
+	}
+	return &CachedCatalog{next: next, shared: shared, local: newLRUCache(size), stats: stats}
+}
+
+func (cc *CachedCatalog) Trending(mediaType, window string, page int) (*domain.CatalogPage, error) {
+	return cached(cc, "trending", fmt.Sprintf("%s:%s:%d", mediaType, window, page), func() (*domain.CatalogPage, error) {
+		return cc.next.Trending(mediaType, window, page)
+	})
+}
+
+func (cc *CachedCatalog) List(mediaType, list string, page int) (*domain.CatalogPage, error) {
+	return cached(cc, "list", fmt.Sprintf("%s:%s:%d", mediaType, list, page), func() (*domain.CatalogPage, error) {
+		return cc.next.List(mediaType, list, page)
+	})
+}
+
+func (cc *CachedCatalog) Discover(q domain.DiscoverQuery) (*domain.CatalogPage, error) {
+	key := fmt.Sprintf("%s:%v:%d:%s:%s:%s:%d", q.MediaType, q.Genres, q.Year, q.ReleasedFrom, q.ReleasedTo, q.SortBy, q.Page)
+	return cached(cc, "discover", key, func() (*domain.CatalogPage, error) {
+		return cc.next.Discover(q)
+	})
+}
+
+// Search is keyed case-insensitively, as TMDB's search is
+func (cc *CachedCatalog) Search(query string, page int) (*domain.CatalogPage, error) {
+	return cached(cc, "search", fmt.Sprintf("%d:%s", page, strings.ToLower(query)), func() (*domain.CatalogPage, error) {
+		return cc.next.Search(query, page)
+	})
+}
+
+// Details, Videos and Images also cache "not found" answers
+func (cc *CachedCatalog) Details(mediaType string, id uint) (*domain.CatalogDetails, error) {
+	return cached(cc, "details", fmt.Sprintf("%s:%d", mediaType, id), func() (*domain.CatalogDetails, error) {
+		return cc.next.Details(mediaType, id)
+	})
+}
+
+func (cc *CachedCatalog) Videos(mediaType string, id uint) (*domain.CatalogVideos, error) {
+	return cached(cc, "videos", fmt.Sprintf("%s:%d", mediaType, id), func() (*domain.CatalogVideos, error) {
+		return cc.next.Videos(mediaType, id)
+	})
+}
+
+func (cc *CachedCatalog) Images(mediaType string, id uint) (*domain.CatalogImages, error) {
+	return cached(cc, "images", fmt.Sprintf("%s:%d", mediaType, id), func() (*domain.CatalogImages, error) {
+		return cc.next.Images(mediaType, id)
+	})
+}
+
+func (cc *CachedCatalog) Genres(mediaType string) ([]domain.CatalogGenre, error) {
+	return cached(cc, "genres", mediaType, func() ([]domain.CatalogGenre, error) {
+		return cc.next.Genres(mediaType)
+	})
+}
+
+// cached adapts a typed provider call to CachedCatalog.get
+func cached[T any](cc *CachedCatalog, endpoint, key string, fetch func() (T, error)) (T, error) {
+	v, err := cc.get(endpoint, endpoint+":"+key,
+		func() (any, error) { return fetch() },
+		func(data []byte) (any, error) {
+			var out T
+			err := json.Unmarshal(data, &out)
+			return out, err
+		})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// sharedEntry is how responses are stored in the shared cache
+type sharedEntry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// get serves key from memory, then the shared store, then fetch
+func (cc *CachedCatalog) get(endpoint, key string, fetch func() (any, error), decode func([]byte) (any, error)) (any, error) {
+	ttl := catalogTTLs[endpoint]
+	stats := cc.stats[endpoint]
+
+	if e, ok := cc.local.get(key); ok {
+		if time.Since(e.fetchedAt) < ttl.fresh {
+			stats.hits.Add(1)
+			return e.value, nil
+		}
+		// Serve the stale copy now and refresh it in the background; a
+		// refresh already in flight for this key is joined, not repeated
+		stats.staleHits.Add(1)
+		cc.group.DoChan(key, func() (any, error) {
+			stats.refreshes.Add(1)
+			v, err := cc.fetch(endpoint, key, fetch)
+			if err != nil {
+				log.Printf("catalog cache refresh %s: %v", key, err)
+			}
+			return v, err
+		})
+		return e.value, nil
+	}
+
+	ran := false
+	v, err, _ := cc.group.Do(key, func() (any, error) {
+		ran = true
+		if v, ok := cc.loadShared(endpoint, key, decode); ok {
+			stats.sharedHits.Add(1)
+			return v, nil
+		}
+		stats.misses.Add(1)
+		return cc.fetch(endpoint, key, fetch)
+	})
+	if !ran {
+		stats.coalesced.Add(1)
+	}
+	return v, err
+}
+
+// fetch calls the provider and stores a successful response in both layers
+func (cc *CachedCatalog) fetch(endpoint, key string, fetch func() (any, error)) (any, error) {
+	ttl := catalogTTLs[endpoint]
+	v, err := fetch()
+	if err != nil {
+		cc.stats[endpoint].errors.Add(1)
+		return nil, err
+	}
+	now := time.Now()
+	cc.local.set(key, v, now, now.Add(ttl.fresh+ttl.stale))
+
+	if cc.shared != nil {
+		value, err := json.Marshal(v)
+		if err == nil {
+			var data []byte
+			data, err = json.Marshal(sharedEntry{FetchedAt: now, Value: value})
+			if err == nil {
+				err = cc.shared.SetCache(sharedCachePrefix+key, data, ttl.fresh+ttl.stale)
+			}
+		}
+		if err != nil {
+			log.Printf("catalog cache store %s: %v", key, err)
+		}
+	}
+	return v, nil
+}
+
+// loadShared copies an entry from the shared store into memory. A stale
+// entry keeps its age, so the next request refreshes it.
+func (cc *CachedCatalog) loadShared(endpoint, key string, decode func([]byte) (any, error)) (any, bool) {
+	if cc.shared == nil {
+		return nil, false
+	}
+	data, ok, err := cc.shared.GetCache(sharedCachePrefix + key)
+	if err != nil {
+		log.Printf("catalog cache load %s: %v", key, err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	var entry sharedEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		log.Printf("catalog cache load %s: %v", key, err)
+		return nil, false
+	}
+	v, err := decode(entry.Value)
+	if err != nil {
+		log.Printf("catalog cache load %s: %v", key, err)
+		return nil, false
+	}
+	ttl := catalogTTLs[endpoint]
+	cc.local.set(key, v, entry.FetchedAt, entry.FetchedAt.Add(ttl.fresh+ttl.stale))
+	return v, true
+}
+
+// endpointStats counts how one endpoint's lookups were answered
+type endpointStats struct {
+	hits, staleHits, sharedHits, misses, coalesced, refreshes, errors atomic.Int64
+}
+
+// CacheEndpointStats is a snapshot of one endpoint's counters. Hits, stale
+// hits, shared hits, misses and coalesced requests add up to all lookups;
+// refreshes and errors count upstream calls.
+type CacheEndpointStats struct {
+	Hits       int64   `json:"hits"`
+	StaleHits  int64   `json:"stale_hits"`
+	SharedHits int64   `json:"shared_hits"`
+	Misses     int64   `json:"misses"`
+	Coalesced  int64   `json:"coalesced"`
+	Refreshes  int64   `json:"refreshes"`
+	Errors     int64   `json:"errors"`
+	HitRate    float64 `json:"hit_rate"` // share of lookups not sent upstream
+}
+
+type CacheStats struct {
+	Entries   int                           `json:"entries"`
+	Capacity  int                           `json:"capacity"`
+	Shared    bool                          `json:"shared"`
+	Endpoints map[string]CacheEndpointStats `json:"endpoints"`
+}
+
+// Stats returns the counters since startup
+func (cc *CachedCatalog) Stats() CacheStats {
+	stats := CacheStats{
+		Entries:   cc.local.len(),
+		Capacity:  cc.local.capacity,
+		Shared:    cc.shared != nil,
+		Endpoints: make(map[string]CacheEndpointStats, len(cc.stats)),
+	}
+	for endpoint, s := range cc.stats {
+		es := CacheEndpointStats{
+			Hits:       s.hits.Load(),
+			StaleHits:  s.staleHits.Load(),
+			SharedHits: s.sharedHits.Load(),
+			Misses:     s.misses.Load(),
+			Coalesced:  s.coalesced.Load(),
+			Refreshes:  s.refreshes.Load(),
+			Errors:     s.errors.Load(),
+		}
+		if total := es.Hits + es.StaleHits + es.SharedHits + es.Misses + es.Coalesced; total > 0 {
+			es.HitRate = float64(total-es.Misses) / float64(total)
+		}
+		stats.Endpoints[endpoint] = es
+	}
+	return stats
+}
+
+// lruCache is a size-bounded map that evicts the least recently used entry
+// and drops entries once they expire
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is the most recently used
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     any
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+func newLRUCache(capacity int) *lruCache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &lruCache{capacity: capacity, order: list.New(), items: map[string]*list.Element{}}
+}
+
+func (l *lruCache) get(key string) (lruEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		return lruEntry{}, false
+	}
+	e := el.Value.(*lruEntry)
+	if time.Now().After(e.expiresAt) {
+		l.order.Remove(el)
+		delete(l.items, key)
+		return lruEntry{}, false
+	}
+	l.order.MoveToFront(el)
+	return *e, true
+}
+
+func (l *lruCache) set(key string, value any, fetchedAt, expiresAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		*el.Value.(*lruEntry) = lruEntry{key: key, value: value, fetchedAt: fetchedAt, expiresAt: expiresAt}
+		l.order.MoveToFront(el)
+		return
+	}
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, value: value, fetchedAt: fetchedAt, expiresAt: expiresAt})
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *lruCache) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	type op struct {
+		set   bool
+		key   string
+		value int
+		want  bool // for gets: whether key is present
+	}
+	tests := []struct {
+		name     string
+		capacity int
+		ops      []op
+		wantLen  int
+	}{
+		{"get missing", 2, []op{{key: "a"}}, 0},
+		{"set and get", 2, []op{{set: true, key: "a", value: 1}, {key: "a", value: 1, want: true}}, 1},
+		{"evicts least recently set", 2, []op{
+			{set: true, key: "a", value: 1}, {set: true, key: "b", value: 2}, {set: true, key: "c", value: 3},
+			{key: "a"}, {key: "b", value: 2, want: true}, {key: "c", value: 3, want: true},
+		}, 2},
+		{"a get keeps an entry", 2, []op{
+			{set: true, key: "a", value: 1}, {set: true, key: "b", value: 2},
+			{key: "a", value: 1, want: true}, {set: true, key: "c", value: 3},
+			{key: "a", value: 1, want: true}, {key: "b"},
+		}, 2},
+		{"overwrite doesn't grow", 2, []op{
+			{set: true, key: "a", value: 1}, {set: true, key: "a", value: 2}, {key: "a", value: 2, want: true},
+		}, 1},
+		{"zero capacity holds one", 0, []op{
+			{set: true, key: "a", value: 1}, {set: true, key: "b", value: 2}, {key: "a"}, {key: "b", value: 2, want: true},
+		}, 1},
+	}
+	for _, tt := range tests {
+		c := newLRUCache(tt.capacity)
+		for i, o := range tt.ops {
+			if o.set {
+				c.set(o.key, o.value, now, later)
+				continue
+			}
+			e, ok := c.get(o.key)
+			if ok != o.want || (ok && e.value != o.value) {
+				t.Errorf("%s: op %d get(%q) = %v, %v; want %v, %v", tt.name, i, o.key, e.value, ok, o.value, o.want)
+			}
+		}
+		if got := c.len(); got != tt.wantLen {
+			t.Errorf("%s: len = %d, want %d", tt.name, got, tt.wantLen)
+		}
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	c := newLRUCache(2)
+	fetched := time.Now().Add(-time.Hour)
+	c.set("old", 1, fetched, time.Now().Add(-time.Second))
+	c.set("fresh", 2, fetched, time.Now().Add(time.Hour))
+	if _, ok := c.get("old"); ok {
+		t.Error("expired entry was returned")
+	}
+	if c.len() != 1 {
+		t.Errorf("len = %d after an expired get, want 1", c.len())
+	}
+	e, ok := c.get("fresh")
+	if !ok || !e.fetchedAt.Equal(fetched) {
+		t.Errorf("fresh entry = %+v, %v; want it with its fetch time", e, ok)
+	}
+}
@@ -0,0 +1,460 @@
+package usecase
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// Progress is saved after every batch, so at most one batch is redone after
+// an interruption
+const (
+	syncIngestBatch = 1000 // export lines per staging write
+	syncFetchBatch  = 100  // detail fetches per round
+)
+
+// ErrSyncRunning is returned when a sync is started while one is in progress
+var ErrSyncRunning = errors.New("catalog sync already running")
+
+// CatalogSyncOptions tunes a sync; the zero value syncs everything once
+type CatalogSyncOptions struct {
+	MinPopularity float64       // export entries less popular than this are skipped
+	MaxFetches    int           // detail fetches per run; 0 means no limit
+	RefreshAfter  time.Duration // refetch movies synced longer ago; 0 never refetches
+}
+
+// CatalogSync fills the Movie table from TMDB. It stages the IDs listed in
+// TMDB's daily movie export (movie_ids_MM_DD_YYYY.json.gz, one JSON object
+// per line), then fetches details for new and stale IDs, most popular first.
+type CatalogSync struct {
+	repo       repository.CatalogSyncRepo
+	catalog    domain.CatalogProvider
+	exportPath string // an export file, or a directory of them
+	opts       CatalogSyncOptions
+	running    sync.Mutex
+}
+
+func NewCatalogSync(repo repository.CatalogSyncRepo, catalog domain.CatalogProvider, exportPath string, opts CatalogSyncOptions) *CatalogSync {
+	return &CatalogSync{repo: repo, catalog: catalog, exportPath: exportPath, opts: opts}
+}
+
+// Start syncs now and then every interval until ctx is cancelled
+func (cs *CatalogSync) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if run, err := cs.Run(ctx); err != nil {
+			log.Printf("catalog sync: %v", err)
+		} else {
+			log.Printf("catalog sync: %s staged %d IDs, fetched %d, failed %d", run.ExportFile, run.Ingested, run.Fetched, run.Failed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run syncs the newest export. An export whose last run was interrupted is
+// resumed; one already ingested only gets a fetch pass for new and stale IDs.
+func (cs *CatalogSync) Run(ctx context.Context) (*domain.CatalogSyncRun, error) {
+	if !cs.running.TryLock() {
+		return nil, ErrSyncRunning
+	}
+	defer cs.running.Unlock()
+
+	path, err := latestExport(cs.exportPath)
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Base(path)
+	run, err := cs.repo.GetLatestSyncRun(file)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	switch {
+	case run == nil:
+		run = &domain.CatalogSyncRun{ExportFile: file, Status: domain.SyncIngesting, StartedAt: now}
+	case run.Status == domain.SyncDone:
+		run = &domain.CatalogSyncRun{ExportFile: file, Status: domain.SyncFetching, StartedAt: now}
+	default:
+		log.Printf("catalog sync: resuming %s (%s, %d lines read)", file, run.Status, run.LinesRead)
+	}
+	run.Error = ""
+	if err := cs.repo.SaveSyncRun(run); err != nil {
+		return nil, err
+	}
+
+	if run.Status == domain.SyncIngesting {
+		err = cs.ingest(ctx, path, run)
+	}
+	if err == nil {
+		err = cs.fetch(ctx, run)
+	}
+	if err != nil {
+		run.Error = err.Error()
+		if saveErr := cs.repo.SaveSyncRun(run); saveErr != nil {
+			log.Printf("catalog sync: saving progress: %v", saveErr)
+		}
+		return run, err
+	}
+	finished := time.Now()
+	run.Status = domain.SyncDone
+	run.FinishedAt = &finished
+	return run, cs.repo.SaveSyncRun(run)
+}
+
+// exportEntry is one line of a TMDB ID export
+type exportEntry struct {
+	ID            uint    `json:"id"`
+	OriginalTitle string  `json:"original_title"`
+	Popularity    float64 `json:"popularity"`
+	Adult         bool    `json:"adult"`
+	Video         bool    `json:"video"`
+}
+
+// ingest stages the export's IDs, starting after run.LinesRead. Adult
+// titles, video releases and unpopular entries are skipped.
+func (cs *CatalogSync) ingest(ctx context.Context, path string, run *domain.CatalogSyncRun) error {
+	r, closeFn, err := openExport(path)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	batch := make([]domain.CatalogSyncItem, 0, syncIngestBatch)
+	line, malformed := 0, 0
+	flush := func() error {
+		if err := cs.repo.UpsertSyncItems(batch); err != nil {
+			return err
+		}
+		run.LinesRead = line
+		run.Ingested += len(batch)
+		batch = batch[:0]
+		return cs.repo.SaveSyncRun(run)
+	}
+
+	for scanner.Scan() {
+		line++
+		if line <= run.LinesRead {
+			continue
+		}
+		var e exportEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.ID == 0 {
+			malformed++
+		} else if !e.Adult && !e.Video && e.Popularity >= cs.opts.MinPopularity {
+			batch = append(batch, domain.CatalogSyncItem{TMDBID: e.ID, OriginalTitle: truncate(e.OriginalTitle, 300), Popularity: e.Popularity})
+		}
+		if line%syncIngestBatch == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", run.ExportFile, err)
+	}
+	if malformed > 0 {
+		log.Printf("catalog sync: skipped %d malformed lines in %s", malformed, run.ExportFile)
+	}
+	run.Status = domain.SyncFetching
+	return flush()
+}
+
+// fetch upserts movies for staged IDs that were never fetched or are stale.
+// Titles TMDB no longer has, or that can't be stored, are marked with an
+// error and retried once stale; ones that lost out to a concurrent upsert
+// are retried in a later batch. A TMDB failure stops the run, leaving the
+// rest of the batch for next time.
+func (cs *CatalogSync) fetch(ctx context.Context, run *domain.CatalogSyncRun) error {
+	var staleBefore time.Time
+	if cs.opts.RefreshAfter > 0 {
+		staleBefore = time.Now().Add(-cs.opts.RefreshAfter)
+	}
+	done := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		limit := syncFetchBatch
+		if cs.opts.MaxFetches > 0 {
+			if done >= cs.opts.MaxFetches {
+				return nil
+			}
+			limit = min(limit, cs.opts.MaxFetches-done)
+		}
+		items, err := cs.repo.ListSyncItemsToFetch(staleBefore, limit)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		var (
+			mu         sync.Mutex
+			wg         sync.WaitGroup
+			sem        = make(chan struct{}, maxConcurrentFetches)
+			fetchErr   error
+			markErr    error
+			fetched    int
+			failed     int
+			fetchedNow = time.Now()
+		)
+		for _, item := range items {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(item domain.CatalogSyncItem) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				d, err := cs.catalog.Details("movie", item.TMDBID)
+				if err != nil {
+					mu.Lock()
+					fetchErr = err
+					failed++
+					mu.Unlock()
+					return
+				}
+				errMsg := ""
+				if d == nil {
+					errMsg = "not found on TMDB"
+				} else if _, err := cs.repo.UpsertMovie(syncedMovie(item, d)); errors.Is(err, repository.ErrRetryable) {
+					// Left unfetched, so a later batch picks it up again
+					log.Printf("catalog sync: storing %d: %v", item.TMDBID, err)
+					mu.Lock()
+					failed++
+					mu.Unlock()
+					return
+				} else if err != nil {
+					errMsg = truncate(err.Error(), 500)
+				}
+				err = cs.repo.MarkSyncItemFetched(item.TMDBID, fetchedNow, errMsg)
+				mu.Lock()
+				if err != nil {
+					markErr = err
+				}
+				if errMsg == "" {
+					fetched++
+				} else {
+					failed++
+				}
+				mu.Unlock()
+			}(item)
+		}
+		wg.Wait()
+
+		done += len(items)
+		run.Fetched += fetched
+		run.Failed += failed
+		if err := cs.repo.SaveSyncRun(run); err != nil {
+			return err
+		}
+		if markErr != nil {
+			return markErr
+		}
+		if fetchErr != nil {
+			return fmt.Errorf("fetching details: %w", fetchErr)
+		}
+	}
+}
+
+// syncedCastNames is how much of the billed cast is kept for search
+const syncedCastNames = 10
+
+// syncedCast is how much of the billed cast is stored as credits
+const syncedCast = 20
+
+// syncedCrewJobs are the crew credits kept; a film's full crew can run to
+// hundreds of people
+var syncedCrewJobs = map[string]bool{
+	"Director":                true,
+	"Screenplay":              true,
+	"Writer":                  true,
+	"Story":                   true,
+	"Novel":                   true,
+	"Producer":                true,
+	"Original Music Composer": true,
+	"Director of Photography": true,
+	"Editor":                  true,
+}
+
+// syncedMovie converts TMDB details to a Movie, falling back to the
+// export's original title
+func syncedMovie(item domain.CatalogSyncItem, d *domain.CatalogDetails) *domain.Movie {
+	id := item.TMDBID
+	originalTitle := d.OriginalTitle
+	if originalTitle == "" {
+		originalTitle = item.OriginalTitle
+	}
+	title := d.Title
+	if title == "" {
+		title = originalTitle
+	}
+	if originalTitle == title {
+		originalTitle = ""
+	}
+	cast := make(domain.StringArray, 0, syncedCastNames)
+	for _, c := range d.Credits.Cast {
+		if len(cast) == syncedCastNames {
+			break
+		}
+		cast = append(cast, truncate(c.Name, 200))
+	}
+	credits := syncedCredits(d.Credits)
+	genres := make([]domain.Genre, 0, len(d.Genres))
+	for _, g := range d.Genres {
+		genres = append(genres, domain.Genre{ID: uint(g.ID), Name: g.Name})
+	}
+	year := 0
+	if len(d.ReleaseDate) >= 4 {
+		year, _ = strconv.Atoi(d.ReleaseDate[:4])
+	}
+	var imdbID *string
+	if d.IMDbID != "" {
+		imdbID = &d.IMDbID
+	}
+	return &domain.Movie{
+		MediaType:     "movie",
+		TMDBID:        &id,
+		IMDbID:        imdbID,
+		Title:         truncate(title, 300),
+		OriginalTitle: truncate(originalTitle, 300),
+		Description:   d.Overview,
+		Year:          year,
+		Genres:        genres,
+		CastNames:     cast,
+		Credits:       credits,
+		Popularity:    d.Popularity,
+	}
+}
+
+// syncedCredits keeps the top-billed cast and the crew in syncedCrewJobs
+func syncedCredits(c domain.CatalogCredits) []domain.Credit {
+	credits := make([]domain.Credit, 0, syncedCast)
+	for _, m := range c.Cast {
+		if len(credits) == syncedCast {
+			break
+		}
+		credits = append(credits, domain.Credit{
+			PersonID:  m.ID,
+			Role:      domain.CreditCast,
+			Character: truncate(m.Character, 500),
+			Order:     m.Order,
+			Person:    syncedPerson(m.ID, m.Name, m.ProfilePath, m.KnownForDepartment),
+		})
+	}
+	type crewKey struct {
+		person uint
+		job    string
+	}
+	seen := map[crewKey]bool{}
+	for _, m := range c.Crew {
+		key := crewKey{m.ID, m.Job}
+		if !syncedCrewJobs[m.Job] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		credits = append(credits, domain.Credit{
+			PersonID:   m.ID,
+			Role:       domain.CreditCrew,
+			Department: truncate(m.Department, 50),
+			Job:        m.Job,
+			Person:     syncedPerson(m.ID, m.Name, m.ProfilePath, m.KnownForDepartment),
+		})
+	}
+	return credits
+}
+
+func syncedPerson(id uint, name, profilePath, department string) *domain.Person {
+	return &domain.Person{
+		ID:                 id,
+		Name:               truncate(name, 200),
+		ProfilePath:        truncate(profilePath, 100),
+		KnownForDepartment: truncate(department, 50),
+	}
+}
+
+// latestExport resolves path to an export file. For a directory it picks
+// the movie export with the latest date in its name.
+func latestExport(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+	matches, err := filepath.Glob(filepath.Join(path, "movie_ids_*.json*"))
+	if err != nil {
+		return "", err
+	}
+	type dated struct {
+		path string
+		date time.Time
+	}
+	var exports []dated
+	for _, m := range matches {
+		name := strings.TrimPrefix(filepath.Base(m), "movie_ids_")
+		name = strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".json")
+		if date, err := time.Parse("01_02_2006", name); err == nil {
+			exports = append(exports, dated{m, date})
+		}
+	}
+	if len(exports) == 0 {
+		return "", fmt.Errorf("no movie_ids_MM_DD_YYYY.json.gz export in %s", path)
+	}
+	sort.Slice(exports, func(i, j int) bool { return exports[i].date.After(exports[j].date) })
+	return exports[0].path, nil
+}
+
+// openExport opens an export, decompressing it if it is gzipped
+func openExport(path string) (io.Reader, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	br := bufio.NewReader(f)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		return gz, func() { gz.Close(); f.Close() }, nil
+	}
+	return br, func() { f.Close() }, nil
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// GenreService keeps the Genre table, seeded with TMDB's lists at migration,
+// in step with TMDB
+type GenreService struct {
+	repo    repository.GenreRepo
+	catalog domain.CatalogProvider
+}
+
+// NewGenreService creates the service; catalog may be nil, leaving the
+// seeded genres as they are
+func NewGenreService(repo repository.GenreRepo, catalog domain.CatalogProvider) *GenreService {
+	return &GenreService{repo: repo, catalog: catalog}
+}
+
+// Start refreshes now and then every interval until ctx is cancelled
+func (gs *GenreService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := gs.Refresh(); err != nil {
+			log.Printf("genre refresh: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh merges TMDB's movie and TV genre lists into the Genre table
+func (gs *GenreService) Refresh() error {
+	if gs.catalog == nil {
+		return nil
+	}
+	for _, mediaType := range []string{"movie", "tv"} {
+		list, err := gs.catalog.Genres(mediaType)
+		if err != nil {
+			return fmt.Errorf("%s genres: %w", mediaType, err)
+		}
+		genres := make([]domain.Genre, 0, len(list))
+		for _, g := range list {
+			if g.ID <= 0 || g.Name == "" {
+				continue
+			}
+			genres = append(genres, domain.Genre{ID: uint(g.ID), Name: g.Name, Movie: mediaType == "movie", TV: mediaType == "tv"})
+		}
+		if err := gs.repo.UpsertGenres(genres); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns all genres, or only those of one media type
+func (gs *GenreService) List(mediaType string) ([]domain.Genre, error) {
+	return gs.repo.ListGenres(mediaType)
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// Pick scoring: each member who wants a title adds 1, must-watch adds a bit
+// more, and TMDB rating breaks ties between equally wanted titles
+const (
+	pickMustWatchBonus = 0.5
+	pickRatingWeight   = 0.25
+	highlyRatedPick    = 7.5
+)
+
+// GroupPicker suggests what a group should watch together from the overlap of
+// its members' watchlists
+type GroupPicker struct {
+	watchlists repository.WatchlistRepo
+	diary      repository.DiaryRepo
+	metadata   *MetadataCache
+}
+
+func NewGroupPicker(watchlists repository.WatchlistRepo, diary repository.DiaryRepo, metadata *MetadataCache) *GroupPicker {
+	return &GroupPicker{watchlists: watchlists, diary: diary, metadata: metadata}
+}
+
+// pickTally is who wants a title
+type pickTally struct {
+	wantedBy  []string
+	mustWatch []string
+}
+
+// Pick returns up to opts.Limit candidates, best first. In intersection mode
+// every member must have the title saved; in overlap mode at least
+// opts.MinMembers must, and titles more members want rank higher.
+func (gp *GroupPicker) Pick(members []domain.PickMember, opts domain.PickOptions) ([]domain.PickCandidate, error) {
+	userIDs := make([]uint, len(members))
+	names := make(map[uint]string, len(members))
+	for i, m := range members {
+		userIDs[i] = m.UserID
+		names[m.UserID] = m.Username
+	}
+
+	items, err := gp.watchlists.ListPersonalWatchlists(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	tallies := map[uint]*pickTally{}
+	for _, item := range items {
+		t := tallies[item.MovieID]
+		if t == nil {
+			t = &pickTally{}
+			tallies[item.MovieID] = t
+		}
+		t.wantedBy = append(t.wantedBy, names[item.UserID])
+		if item.Priority == domain.PriorityMustWatch {
+			t.mustWatch = append(t.mustWatch, names[item.UserID])
+		}
+	}
+
+	required := len(members)
+	if opts.Mode == domain.PickOverlap {
+		required = opts.MinMembers
+	}
+	if !opts.IncludeWatched {
+		watched, err := gp.diary.ListWatchedIDs(userIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range watched {
+			delete(tallies, id)
+		}
+	}
+	var ids []uint
+	for id, t := range tallies {
+		if len(t.wantedBy) >= required {
+			ids = append(ids, id)
+		}
+	}
+	meta, err := gp.metadata.Lookup(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]bool, len(opts.ExcludeGenres))
+	for _, g := range opts.ExcludeGenres {
+		excluded[strings.ToLower(strings.TrimSpace(g))] = true
+	}
+	candidates := make([]domain.PickCandidate, 0, len(ids))
+	for _, id := range ids {
+		m := meta[id]
+		if opts.MediaType != "" && m.MediaType != opts.MediaType {
+			continue
+		}
+		if opts.MaxRuntime > 0 && m.Runtime > opts.MaxRuntime {
+			continue
+		}
+		genres := []string{}
+		if m.Genres != "" {
+			genres = strings.Split(m.Genres, ",")
+		}
+		if hasExcludedGenre(genres, excluded) {
+			continue
+		}
+		candidates = append(candidates, newPickCandidate(id, m, genres, tallies[id], len(members), opts))
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Rating != b.Rating {
+			return a.Rating > b.Rating
+		}
+		return a.MovieID < b.MovieID
+	})
+	if opts.Limit > 0 && len(candidates) > opts.Limit {
+		candidates = candidates[:opts.Limit]
+	}
+	return candidates, nil
+}
+
+// newPickCandidate scores a title and explains the score
+func newPickCandidate(id uint, m domain.MovieMetadata, genres []string, t *pickTally, groupSize int, opts domain.PickOptions) domain.PickCandidate {
+	sort.Strings(t.wantedBy)
+	sort.Strings(t.mustWatch)
+	score := float64(len(t.wantedBy)) + pickMustWatchBonus*float64(len(t.mustWatch))
+	score = score/float64(groupSize) + pickRatingWeight*m.Rating/10
+
+	var reasons []string
+	if len(t.wantedBy) == groupSize {
+		reasons = append(reasons, "On everyone's watchlist")
+	} else {
+		reasons = append(reasons, fmt.Sprintf("On %d of %d watchlists", len(t.wantedBy), groupSize))
+	}
+	if len(t.mustWatch) > 0 {
+		reasons = append(reasons, "Must-watch for "+strings.Join(t.mustWatch, ", "))
+	}
+	if m.Rating >= highlyRatedPick {
+		reasons = append(reasons, fmt.Sprintf("Rated %.1f on TMDB", m.Rating))
+	}
+	if opts.MaxRuntime > 0 && m.Runtime > 0 {
+		reasons = append(reasons, fmt.Sprintf("Runs %d min, within %d", m.Runtime, opts.MaxRuntime))
+	}
+	if !opts.IncludeWatched {
+		reasons = append(reasons, "Nobody has logged it yet")
+	}
+
+	return domain.PickCandidate{
+		MovieID:     id,
+		MediaType:   m.MediaType,
+		Title:       m.Title,
+		PosterPath:  m.PosterPath,
+		Year:        m.ReleaseYear,
+		Runtime:     m.Runtime,
+		Rating:      m.Rating,
+		Genres:      genres,
+		Score:       math.Round(score*1000) / 1000,
+		WantedBy:    t.wantedBy,
+		MustWatchBy: t.mustWatch,
+		Reasons:     reasons,
+	}
+}
+
+func hasExcludedGenre(genres []string, excluded map[string]bool) bool {
+	for _, g := range genres {
+		if excluded[strings.ToLower(strings.TrimSpace(g))] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+// ImageSizes are the proxy's presets: the width images are scaled down to,
+// named like TMDB's so client URLs map over directly. "original" is passed
+// through untouched.
+var ImageSizes = map[string]int{
+	"w92":      92,
+	"w154":     154,
+	"w185":     185,
+	"w342":     342,
+	"w500":     500,
+	"w780":     780,
+	"w1280":    1280,
+	"original": 0,
+}
+
+// Image proxy errors
+var (
+	ErrUnknownImageSize = errors.New("unknown image size")
+	ErrInvalidImagePath = errors.New("invalid image path")
+	ErrUnsupportedImage = errors.New("unsupported image")
+)
+
+// imagePathPattern matches TMDB image file paths such as
+// /wo2hJpn04vbtmh0B9utCFdsQhxM.jpg. Nothing else reaches the origin.
+var imagePathPattern = regexp.MustCompile(`^/[A-Za-z0-9_-]{1,100}\.(jpg|jpeg|png)$`)
+
+// maxSourcePixels refuses images that would take too much memory to decode
+const maxSourcePixels = 50_000_000
+
+// imageJPEGQuality balances size and artefacts for posters and backdrops
+const imageJPEGQuality = 82
+
+// imageFetchTimeout bounds fetching and resizing one image. Callers share
+// the work, so one giving up doesn't cancel it for the rest.
+const imageFetchTimeout = 30 * time.Second
+
+// Image is a proxied image ready to serve
+type Image struct {
+	Data        []byte
+	ContentType string
+	ETag        string // strong, quoted
+}
+
+// ImageProxy serves origin images scaled to preset sizes. Results are kept
+// in a blob cache; concurrent misses for the same image share one fetch.
+type ImageProxy struct {
+	origin domain.ImageOrigin
+	cache  domain.BlobCache
+	group  singleflight.Group
+}
+
+// NewImageProxy creates the proxy; cache may be nil to resize every request
+func NewImageProxy(origin domain.ImageOrigin, cache domain.BlobCache) *ImageProxy {
+	return &ImageProxy{origin: origin, cache: cache}
+}
+
+// Get returns the image at path scaled to size, nil if the origin has none
+func (p *ImageProxy) Get(ctx context.Context, size, path string) (*Image, error) {
+	width, ok := ImageSizes[size]
+	if !ok {
+		return nil, ErrUnknownImageSize
+	}
+	if !imagePathPattern.MatchString(path) {
+		return nil, ErrInvalidImagePath
+	}
+	key := size + path
+
+	if p.cache != nil {
+		data, ok, err := p.cache.GetBlob(key)
+		if err != nil {
+			log.Printf("image cache: reading %s: %v", key, err)
+		} else if ok {
+			return newImage(data), nil
+		}
+	}
+
+	ch := p.group.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), imageFetchTimeout)
+		defer cancel()
+		src, err := p.origin.FetchImage(ctx, path)
+		if err != nil || src == nil {
+			return nil, err
+		}
+		data, err := scaleImage(src, width)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrUnsupportedImage, path, err)
+		}
+		if p.cache != nil {
+			if err := p.cache.PutBlob(key, data); err != nil {
+				log.Printf("image cache: writing %s: %v", key, err)
+			}
+		}
+		return data, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil || res.Val == nil {
+			return nil, res.Err
+		}
+		return newImage(res.Val.([]byte)), nil
+	}
+}
+
+func newImage(data []byte) *Image {
+	sum := sha256.Sum256(data)
+	return &Image{
+		Data:        data,
+		ContentType: http.DetectContentType(data),
+		ETag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}
+}
+
+// scaleImage shrinks a JPEG or PNG to width and re-encodes it: as PNG if it
+// has transparency, JPEG otherwise. Images no wider than width, and
+// width 0, are returned as they are.
+func scaleImage(src []byte, width int) ([]byte, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, fmt.Errorf("format %s", format)
+	}
+	if width == 0 || cfg.Width <= width {
+		return src, nil
+	}
+	if cfg.Width*cfg.Height > maxSourcePixels {
+		return nil, fmt.Errorf("%dx%d is too large", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+
+	opaque := true
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		opaque = o.Opaque()
+	}
+	resized := resizeImage(img, width)
+	var buf bytes.Buffer
+	if opaque {
+		err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: imageJPEGQuality})
+	} else {
+		err = png.Encode(&buf, resized)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
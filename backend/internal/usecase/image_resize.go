@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// boxWeight is one destination pixel's share of a run of source pixels
+type boxWeight struct {
+	start   int
+	weights []float32 // sum to 1
+}
+
+// boxWeights maps src pixels onto dst pixels by area: each destination
+// pixel averages the source pixels it covers, weighting partly covered ones
+// by how much of them it covers. Good for downscaling, which is all the
+// proxy does.
+func boxWeights(src, dst int) []boxWeight {
+	scale := float64(src) / float64(dst)
+	out := make([]boxWeight, dst)
+	for i := range out {
+		lo := float64(i) * scale
+		hi := lo + scale
+		start := int(lo)
+		end := min(int(math.Ceil(hi)), src)
+		w := make([]float32, end-start)
+		for j := start; j < end; j++ {
+			cover := math.Min(hi, float64(j+1)) - math.Max(lo, float64(j))
+			w[j-start] = float32(cover / scale)
+		}
+		out[i] = boxWeight{start: start, weights: w}
+	}
+	return out
+}
+
+// resizeImage scales img down to width, keeping its aspect ratio. Rows are
+// resized first, then columns; premultiplied alpha keeps transparent edges
+// from darkening.
+func resizeImage(img image.Image, width int) *image.RGBA {
+	b := img.Bounds()
+	srcW, srcH := b.Dx(), b.Dy()
+	height := max(1, int(math.Round(float64(srcH)*float64(width)/float64(srcW))))
+
+	src, ok := img.(*image.RGBA)
+	if !ok || src.Rect.Min != (image.Point{}) {
+		src = image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+		draw.Draw(src, src.Rect, img, b.Min, draw.Src)
+	}
+
+	// Horizontal pass into a float buffer, srcH rows of width pixels
+	xw := boxWeights(srcW, width)
+	tmp := make([]float32, srcH*width*4)
+	for y := 0; y < srcH; y++ {
+		row := src.Pix[y*src.Stride:]
+		out := tmp[y*width*4:]
+		for x, bw := range xw {
+			var r, g, bl, a float32
+			for k, w := range bw.weights {
+				p := row[(bw.start+k)*4:]
+				r += float32(p[0]) * w
+				g += float32(p[1]) * w
+				bl += float32(p[2]) * w
+				a += float32(p[3]) * w
+			}
+			out[x*4], out[x*4+1], out[x*4+2], out[x*4+3] = r, g, bl, a
+		}
+	}
+
+	// Vertical pass into the result
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	yw := boxWeights(srcH, height)
+	acc := make([]float32, width*4)
+	for y, bw := range yw {
+		clear(acc)
+		for k, w := range bw.weights {
+			row := tmp[(bw.start+k)*width*4 : (bw.start+k+1)*width*4]
+			for x, v := range row {
+				acc[x] += v * w
+			}
+		}
+		out := dst.Pix[y*dst.Stride:]
+		for x, v := range acc {
+			out[x] = clampByte(v)
+		}
+	}
+	return dst
+}
+
+func clampByte(v float32) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	default:
+		return uint8(v + 0.5)
+	}
+}
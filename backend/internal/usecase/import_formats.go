@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Import sources
+const (
+	ImportLetterboxd = "letterboxd"
+	ImportIMDb       = "imdb"
+)
+
+// ErrUnsupportedImport is returned when an upload isn't a recognised export
+var ErrUnsupportedImport = errors.New("unsupported import file")
+
+// maxImportEntryBytes caps a CSV file unpacked from an export archive.
+// Letterboxd exports are a few MB even for long diaries.
+const maxImportEntryBytes = 50 << 20
+
+// importRow is one title read from an export file
+type importRow struct {
+	File      string
+	Line      int
+	Title     string
+	Year      int
+	IMDbID    string
+	TMDBID    uint
+	MediaType string    // "movie" or "tv"
+	Diary     bool      // a viewing rather than a watchlist entry
+	Date      time.Time // date added for watchlist rows, watched date for diary rows
+	Rating    float64   // 0-10, 0 when unrated
+	Rewatch   bool
+	Review    string
+	Skip      string // reason the row can't be imported, if any
+}
+
+// parseImport detects the export format and returns its rows. source may be
+// empty, ImportLetterboxd or ImportIMDb.
+func parseImport(filename string, data []byte, source string) (string, []importRow, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		if source == ImportIMDb {
+			return "", nil, fmt.Errorf("%w: IMDb exports are CSV files", ErrUnsupportedImport)
+		}
+		rows, err := parseLetterboxdZip(data)
+		return ImportLetterboxd, rows, err
+	}
+
+	header, records, err := readCSV(data)
+	if err != nil {
+		return "", nil, err
+	}
+	switch {
+	case header.has("Const") && source != ImportLetterboxd:
+		return ImportIMDb, parseIMDbCSV(filename, header, records), nil
+	case isLetterboxdHeader(header) && source != ImportIMDb:
+		return ImportLetterboxd, parseLetterboxdCSV(filename, header, records), nil
+	}
+	return "", nil, fmt.Errorf("%w: expected a Letterboxd or IMDb CSV export", ErrUnsupportedImport)
+}
+
+// parseLetterboxdZip reads watchlist.csv and diary.csv (or watched.csv when
+// there is no diary) from a Letterboxd account export
+func parseLetterboxdZip(data []byte) ([]importRow, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImport, err)
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	names := []string{"watchlist.csv", "diary.csv"}
+	if files["diary.csv"] == nil {
+		names[1] = "watched.csv"
+	}
+	var rows []importRow
+	found := false
+	for _, name := range names {
+		f := files[name]
+		if f == nil {
+			continue
+		}
+		found = true
+		if f.UncompressedSize64 > maxImportEntryBytes {
+			return nil, fmt.Errorf("%w: %s is too large", ErrUnsupportedImport, name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		// The header's size can lie, so bound the read as well
+		content, err := io.ReadAll(io.LimitReader(rc, maxImportEntryBytes+1))
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(content) > maxImportEntryBytes {
+			return nil, fmt.Errorf("%w: %s is too large", ErrUnsupportedImport, name)
+		}
+		header, records, err := readCSV(content)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, parseLetterboxdCSV(name, header, records)...)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: no watchlist.csv or diary.csv in archive", ErrUnsupportedImport)
+	}
+	return rows, nil
+}
+
+// isLetterboxdHeader accepts Letterboxd's export columns (Name, Year) and
+// its import format (Title, tmdbID, imdbID), which our own export writes
+func isLetterboxdHeader(h csvHeader) bool {
+	return (h.has("Name") && h.has("Year")) || (h.has("Title") && h.has("Year")) ||
+		h.has("tmdbID") || h.has("imdbID")
+}
+
+// parseLetterboxdCSV handles watchlist.csv, diary.csv, watched.csv and
+// ratings.csv, and files in Letterboxd's import format. Files with a Watched
+// Date or Rating column become diary rows.
+func parseLetterboxdCSV(file string, h csvHeader, records [][]string) []importRow {
+	diary := h.has("Watched Date") || h.has("Rating") || file == "watched.csv"
+	rows := make([]importRow, 0, len(records))
+	for i, rec := range records {
+		row := importRow{
+			File:      file,
+			Line:      i + 2,
+			Title:     h.get(rec, "Name"),
+			Year:      atoi(h.get(rec, "Year")),
+			IMDbID:    h.get(rec, "imdbID"),
+			TMDBID:    uint(atoi(h.get(rec, "tmdbID"))),
+			MediaType: "movie",
+			Diary:     diary,
+			Date:      parseDate(h.get(rec, "Date")),
+			Rewatch:   strings.EqualFold(h.get(rec, "Rewatch"), "yes"),
+			Review:    h.get(rec, "Review"),
+		}
+		if row.Title == "" {
+			row.Title = h.get(rec, "Title")
+		}
+		if d := parseDate(h.get(rec, "Watched Date")); !d.IsZero() {
+			row.Date = d
+		}
+		// Letterboxd rates in half stars out of 5
+		if r, err := strconv.ParseFloat(h.get(rec, "Rating"), 64); err == nil {
+			row.Rating = r * 2
+		}
+		if row.Title == "" && row.IMDbID == "" && row.TMDBID == 0 {
+			row.Skip = "missing title"
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// parseIMDbCSV handles IMDb list, watchlist and ratings exports. Rated rows
+// become diary entries; everything else goes on the watchlist.
+func parseIMDbCSV(file string, h csvHeader, records [][]string) []importRow {
+	rows := make([]importRow, 0, len(records))
+	for i, rec := range records {
+		row := importRow{
+			File:      file,
+			Line:      i + 2,
+			Title:     h.get(rec, "Title"),
+			Year:      atoi(h.get(rec, "Year")),
+			IMDbID:    h.get(rec, "Const"),
+			MediaType: "movie",
+			Date:      parseDate(h.get(rec, "Created")),
+		}
+		if r, err := strconv.ParseFloat(h.get(rec, "Your Rating"), 64); err == nil && r > 0 {
+			row.Diary = true
+			row.Rating = r
+			row.Date = parseDate(h.get(rec, "Date Rated"))
+		}
+		// Watchlist items are bare TMDB IDs hydrated as movies first, and TMDB
+		// movie and TV IDs overlap, so a show would come back as some film
+		switch strings.ToLower(strings.ReplaceAll(h.get(rec, "Title Type"), " ", "")) {
+		case "tvseries", "tvminiseries":
+			row.Skip = "TV series can't be imported to the watchlist"
+		case "tvepisode", "podcastepisode", "videogame":
+			row.Skip = "unsupported title type " + h.get(rec, "Title Type")
+		}
+		if row.Title == "" && row.IMDbID == "" {
+			row.Skip = "missing title"
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// csvHeader maps column names to indexes
+type csvHeader map[string]int
+
+func (h csvHeader) has(name string) bool {
+	_, ok := h[name]
+	return ok
+}
+
+func (h csvHeader) get(rec []string, name string) string {
+	i, ok := h[name]
+	if !ok || i >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[i])
+}
+
+func readCSV(data []byte) (csvHeader, [][]string, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedImport, err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("%w: empty file", ErrUnsupportedImport)
+	}
+	header := csvHeader{}
+	for i, name := range records[0] {
+		header[strings.TrimSpace(name)] = i
+	}
+	return header, records[1:], nil
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func parseDate(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
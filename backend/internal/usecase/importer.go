@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// maxImportRows bounds a single upload; each unique title may cost TMDB calls
+const maxImportRows = 5000
+
+// Fuzzy matching thresholds on a 0-1 title similarity score
+const (
+	matchThreshold     = 0.9  // best candidate is accepted at or above this
+	ambiguousThreshold = 0.6  // below this a row fails instead of being ambiguous
+	matchMargin        = 0.05 // best must beat the runner-up by this much
+)
+
+// ErrImportTooLarge is returned when an upload has more than maxImportRows rows
+var ErrImportTooLarge = fmt.Errorf("import exceeds %d rows", maxImportRows)
+
+// ImportReport summarises an import
+type ImportReport struct {
+	Source         string            `json:"source"`
+	Rows           int               `json:"rows"`
+	DryRun         bool              `json:"dry_run"`
+	WatchlistAdded int               `json:"watchlist_added"`
+	DiaryAdded     int               `json:"diary_added"`
+	Matched        []ImportRowResult `json:"matched"`
+	Ambiguous      []ImportRowResult `json:"ambiguous"`
+	Failed         []ImportRowResult `json:"failed"`
+}
+
+// ImportRowResult is the outcome for one row of the export
+type ImportRowResult struct {
+	File       string            `json:"file"`
+	Line       int               `json:"line"`
+	Title      string            `json:"title"`
+	Year       int               `json:"year,omitempty"`
+	TMDBID     uint              `json:"tmdb_id,omitempty"`
+	MatchedBy  string            `json:"matched_by,omitempty"` // tmdb_id, imdb_id, exact or fuzzy
+	Candidates []ImportCandidate `json:"candidates,omitempty"`
+	Reason     string            `json:"reason,omitempty"`
+}
+
+// ImportCandidate is a possible TMDB match for an ambiguous row
+type ImportCandidate struct {
+	TMDBID uint    `json:"tmdb_id"`
+	Title  string  `json:"title"`
+	Year   int     `json:"year,omitempty"`
+	Score  float64 `json:"score"`
+}
+
+// Importer brings watchlists and diaries in from other services
+type Importer struct {
+	watchlist repository.WatchlistRepo
+	diary     repository.DiaryRepo
+	matcher   domain.TitleMatcher
+}
+
+func NewImporter(w repository.WatchlistRepo, d repository.DiaryRepo, matcher domain.TitleMatcher) *Importer {
+	return &Importer{watchlist: w, diary: d, matcher: matcher}
+}
+
+// matchKey identifies rows that resolve to the same title
+type matchKey struct {
+	tmdbID                   uint
+	imdbID, mediaType, title string
+	year                     int
+}
+
+// matchOutcome is the shared result for every row with the same matchKey
+type matchOutcome struct {
+	tmdbID     uint
+	matchedBy  string
+	candidates []ImportCandidate
+	reason     string
+}
+
+// Import parses an export file, matches its rows to TMDB IDs and, unless
+// dryRun is set, writes matched rows to the user's watchlist and diary.
+func (im *Importer) Import(userID uint, filename string, data []byte, source string, dryRun bool) (*ImportReport, error) {
+	source, rows, err := parseImport(filename, data, source)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > maxImportRows {
+		return nil, ErrImportTooLarge
+	}
+
+	outcomes := im.matchAll(rows)
+
+	report := &ImportReport{
+		Source:    source,
+		Rows:      len(rows),
+		DryRun:    dryRun,
+		Matched:   []ImportRowResult{},
+		Ambiguous: []ImportRowResult{},
+		Failed:    []ImportRowResult{},
+	}
+	var items []domain.WatchlistItem
+	var entries []domain.DiaryEntry
+	for _, row := range rows {
+		res := ImportRowResult{File: row.File, Line: row.Line, Title: row.Title, Year: row.Year}
+		if row.Skip != "" {
+			res.Reason = row.Skip
+			report.Failed = append(report.Failed, res)
+			continue
+		}
+		out := outcomes[keyOf(row)]
+		switch {
+		case out.tmdbID != 0:
+			res.TMDBID = out.tmdbID
+			res.MatchedBy = out.matchedBy
+			report.Matched = append(report.Matched, res)
+		case len(out.candidates) > 0:
+			res.Candidates = out.candidates
+			report.Ambiguous = append(report.Ambiguous, res)
+			continue
+		default:
+			res.Reason = out.reason
+			report.Failed = append(report.Failed, res)
+			continue
+		}
+
+		if row.Diary {
+			watched := row.Date
+			if watched.IsZero() {
+				watched = time.Now()
+			}
+			entries = append(entries, domain.DiaryEntry{
+				UserID:    userID,
+				MovieID:   out.tmdbID,
+				WatchedOn: watched,
+				Rating:    row.Rating,
+				Rewatch:   row.Rewatch,
+				Review:    row.Review,
+				Source:    source,
+			})
+		} else {
+			items = append(items, domain.WatchlistItem{MovieID: out.tmdbID, AddedAt: row.Date})
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+	if report.WatchlistAdded, err = im.watchlist.ImportWatchlist(userID, items); err != nil {
+		return nil, err
+	}
+	if report.DiaryAdded, err = im.diary.AddDiaryEntries(entries); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// matchAll resolves each distinct title once, a few at a time
+func (im *Importer) matchAll(rows []importRow) map[matchKey]matchOutcome {
+	outcomes := map[matchKey]matchOutcome{}
+	var keys []matchKey
+	for _, row := range rows {
+		if row.Skip != "" {
+			continue
+		}
+		k := keyOf(row)
+		if _, seen := outcomes[k]; !seen {
+			outcomes[k] = matchOutcome{}
+			keys = append(keys, k)
+		}
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, k := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(k matchKey) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			out := im.match(k)
+			mu.Lock()
+			outcomes[k] = out
+			mu.Unlock()
+		}(k)
+	}
+	wg.Wait()
+	return outcomes
+}
+
+// match takes a TMDB ID as given, then tries the IMDb ID, then a title+year
+// search, then the title alone
+func (im *Importer) match(k matchKey) matchOutcome {
+	if k.tmdbID != 0 {
+		return matchOutcome{tmdbID: k.tmdbID, matchedBy: "tmdb_id"}
+	}
+	if k.imdbID != "" {
+		m, err := im.matcher.FindByIMDbID(k.imdbID)
+		if err != nil {
+			return matchOutcome{reason: "lookup failed: " + err.Error()}
+		}
+		if m != nil && m.MediaType == "tv" {
+			return matchOutcome{reason: "IMDb ID is a TV series, which can't be imported to the watchlist"}
+		}
+		if m != nil {
+			return matchOutcome{tmdbID: m.TMDBID, matchedBy: "imdb_id"}
+		}
+	}
+	if k.title == "" {
+		return matchOutcome{reason: "IMDb ID not found on TMDB"}
+	}
+
+	results, err := im.matcher.SearchTitle(k.mediaType, k.title, k.year)
+	if err == nil && len(results) == 0 && k.year > 0 {
+		results, err = im.matcher.SearchTitle(k.mediaType, k.title, 0)
+	}
+	if err != nil {
+		return matchOutcome{reason: "search failed: " + err.Error()}
+	}
+	if len(results) == 0 {
+		return matchOutcome{reason: "no results on TMDB"}
+	}
+
+	candidates := scoreCandidates(k.title, k.year, results)
+	best := candidates[0]
+	clearWinner := len(candidates) == 1 || best.Score-candidates[1].Score >= matchMargin
+	switch {
+	case best.Score >= matchThreshold && clearWinner:
+		by := "fuzzy"
+		if best.Score == 1 {
+			by = "exact"
+		}
+		return matchOutcome{tmdbID: best.TMDBID, matchedBy: by}
+	case best.Score >= ambiguousThreshold:
+		if len(candidates) > 3 {
+			candidates = candidates[:3]
+		}
+		return matchOutcome{candidates: candidates}
+	}
+	return matchOutcome{reason: "no close title match"}
+}
+
+// scoreCandidates ranks search results by title similarity, penalising year
+// mismatches. A one-year difference is common between regional release dates.
+func scoreCandidates(title string, year int, results []domain.MovieMetadata) []ImportCandidate {
+	want := normalizeTitle(title)
+	out := make([]ImportCandidate, 0, len(results))
+	for _, r := range results {
+		score := similarity(want, normalizeTitle(r.Title))
+		if year > 0 && r.ReleaseYear > 0 {
+			switch diff := year - r.ReleaseYear; {
+			case diff == 0:
+			case diff == 1 || diff == -1:
+				score -= 0.05
+			default:
+				score -= 0.3
+			}
+		}
+		if score < 0 {
+			score = 0
+		}
+		out = append(out, ImportCandidate{TMDBID: r.TMDBID, Title: r.Title, Year: r.ReleaseYear, Score: score})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+func keyOf(row importRow) matchKey {
+	return matchKey{tmdbID: row.TMDBID, imdbID: row.IMDbID, mediaType: row.MediaType, title: row.Title, year: row.Year}
+}
+
+// normalizeTitle lowercases, drops punctuation and a leading article so that
+// "The Godfather: Part II" and "godfather part ii" compare equal
+func normalizeTitle(s string) string {
+	s = strings.ToLower(strings.ReplaceAll(s, "&", " and "))
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || unicode.IsPunct(r):
+			b.WriteRune(' ')
+		}
+	}
+	s = strings.Join(strings.Fields(b.String()), " ")
+	return strings.TrimPrefix(s, "the ")
+}
+
+// similarity is 1 minus the normalised Levenshtein distance
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+// IsImportInputError reports whether err was caused by the uploaded file
+func IsImportInputError(err error) bool {
+	return errors.Is(err, ErrUnsupportedImport) || errors.Is(err, ErrImportTooLarge)
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"The Godfather: Part II", "godfather part ii"},
+		{"godfather part ii", "godfather part ii"},
+		{"Fast & Furious", "fast and furious"},
+		{"  Amélie  ", "amélie"},
+		{"WALL·E", "wall e"},
+		{"Theodore Rex", "theodore rex"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeTitle(tt.in); got != tt.want {
+			t.Errorf("normalizeTitle(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"inception", "inception", 1},
+		{"inception", "", 0},
+		{"abc", "xyz", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"interstellar", "intersteller", 1 - 1.0/12},
+		{"amélie", "amelie", 1 - 1.0/6}, // runes, not bytes
+	}
+	for _, tt := range tests {
+		got := similarity(tt.a, tt.b)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if back := similarity(tt.b, tt.a); math.Abs(back-got) > 1e-9 {
+			t.Errorf("similarity(%q, %q) = %v, not symmetric with %v", tt.b, tt.a, back, got)
+		}
+	}
+}
+
+func TestScoreCandidates(t *testing.T) {
+	results := []domain.MovieMetadata{
+		{TMDBID: 1, Title: "Dune", ReleaseYear: 1984},
+		{TMDBID: 2, Title: "Dune", ReleaseYear: 2021},
+		{TMDBID: 3, Title: "Dune: Part Two", ReleaseYear: 2024},
+	}
+	got := scoreCandidates("Dune", 2021, results)
+	if got[0].TMDBID != 2 || got[0].Score != 1 {
+		t.Fatalf("best = %+v, want the 2021 Dune scoring 1", got[0])
+	}
+	if got[1].TMDBID != 1 || got[1].Score != 0.7 {
+		t.Errorf("runner-up = %+v, want the 1984 Dune scoring 0.7", got[1])
+	}
+	// A year off by one, as between regional releases, costs little
+	if got := scoreCandidates("Dune", 2020, results[1:2]); got[0].Score != 0.95 {
+		t.Errorf("off-by-one year score = %v, want 0.95", got[0].Score)
+	}
+}
+
+func TestParseImportIMDb(t *testing.T) {
+	csv := "Const,Your Rating,Date Rated,Title,Title Type,Year,Created\n" +
+		"tt1375666,9,2024-03-01,Inception,movie,2010,2024-01-01\n" +
+		"tt0816692,,,Interstellar,Movie,2014,2024-02-02\n" +
+		"tt0903747,,,Breaking Bad,TV Series,2008,2024-02-03\n" +
+		"tt0959621,,,Pilot,TV Episode,2008,2024-02-04\n" +
+		",,,,movie,,\n"
+	source, rows, err := parseImport("ratings.csv", []byte(csv), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != ImportIMDb {
+		t.Errorf("source = %q, want %q", source, ImportIMDb)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("rows = %d, want 5", len(rows))
+	}
+	inception := rows[0]
+	if !inception.Diary || inception.Rating != 9 || inception.IMDbID != "tt1375666" ||
+		!inception.Date.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("rated row = %+v, want a diary entry rated 9 on 2024-03-01", inception)
+	}
+	if r := rows[1]; r.Diary || r.Skip != "" || r.Year != 2014 || r.Line != 3 {
+		t.Errorf("unrated row = %+v, want a watchlist row on line 3", r)
+	}
+	for _, i := range []int{2, 3, 4} {
+		if rows[i].Skip == "" {
+			t.Errorf("row %d (%q) isn't skipped", i, rows[i].Title)
+		}
+	}
+}
+
+func TestParseImportLetterboxd(t *testing.T) {
+	diary := "Date,Name,Year,Letterboxd URI,Rating,Rewatch,Tags,Watched Date\n" +
+		"2024-05-02,Dune: Part Two,2024,https://boxd.it/abc,4.5,Yes,,2024-05-01\n"
+	watchlist := "Date,Name,Year,Letterboxd URI\n" +
+		"2024-04-01,Perfect Days,2023,https://boxd.it/def\n"
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"diary.csv": diary, "watchlist.csv": watchlist, "profile.csv": "x\n"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	source, rows, err := parseImport("letterboxd.zip", buf.Bytes(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != ImportLetterboxd || len(rows) != 2 {
+		t.Fatalf("source %q with %d rows, want letterboxd with 2", source, len(rows))
+	}
+	byFile := map[string]importRow{}
+	for _, r := range rows {
+		byFile[r.File] = r
+	}
+	d := byFile["diary.csv"]
+	if !d.Diary || d.Rating != 9 || !d.Rewatch || d.Title != "Dune: Part Two" ||
+		!d.Date.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("diary row = %+v, want a rewatch rated 9/10 on the watched date", d)
+	}
+	if w := byFile["watchlist.csv"]; w.Diary || w.Title != "Perfect Days" || w.Year != 2023 {
+		t.Errorf("watchlist row = %+v", w)
+	}
+}
+
+// Letterboxd's import format names titles by tmdbID, imdbID or Title. The
+// round trip through our own export is tested with the exporter.
+func TestParseImportLetterboxdImportFormat(t *testing.T) {
+	csv := "tmdbID,imdbID,Title,Year,Tags\n" +
+		"27205,tt1375666,Inception,2010,\"heist, dreams\"\n" +
+		"157336,,Interstellar,,\n" +
+		",,,,\n"
+	source, rows, err := parseImport("watchlist-letterboxd.csv", []byte(csv), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != ImportLetterboxd || len(rows) != 3 {
+		t.Fatalf("source %q with %d rows, want letterboxd with 3", source, len(rows))
+	}
+	if r := rows[0]; r.TMDBID != 27205 || r.IMDbID != "tt1375666" || r.Title != "Inception" || r.Year != 2010 || r.Diary {
+		t.Errorf("first row = %+v", r)
+	}
+	if r := rows[1]; r.TMDBID != 157336 || r.Title != "Interstellar" || r.Skip != "" {
+		t.Errorf("second row = %+v", r)
+	}
+	if rows[2].Skip == "" {
+		t.Errorf("blank row isn't skipped: %+v", rows[2])
+	}
+}
+
+func TestParseImportRejects(t *testing.T) {
+	tests := []struct {
+		name, file, data, source string
+	}{
+		{"empty", "x.csv", "", ""},
+		{"unknown columns", "x.csv", "foo,bar\n1,2\n", ""},
+		{"IMDb file as Letterboxd", "x.csv", "Const,Title\ntt1,A\n", ImportLetterboxd},
+		{"zip as IMDb", "x.zip", "PK\x03\x04rest", ImportIMDb},
+		{"zip without CSVs", "x.zip", emptyZip(t), ""},
+	}
+	for _, tt := range tests {
+		if _, _, err := parseImport(tt.file, []byte(tt.data), tt.source); !errors.Is(err, ErrUnsupportedImport) {
+			t.Errorf("%s: err = %v, want ErrUnsupportedImport", tt.name, err)
+		}
+	}
+}
+
+func emptyZip(t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := zip.NewWriter(&buf).Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// fakeMatcher answers from fixed IMDb IDs and search results
+type fakeMatcher struct {
+	byIMDb map[string]*domain.MovieMetadata
+	search map[string][]domain.MovieMetadata // by title
+}
+
+func (f fakeMatcher) FindByIMDbID(imdbID string) (*domain.MovieMetadata, error) {
+	return f.byIMDb[imdbID], nil
+}
+
+func (f fakeMatcher) SearchTitle(mediaType, title string, year int) ([]domain.MovieMetadata, error) {
+	var out []domain.MovieMetadata
+	for _, m := range f.search[title] {
+		if year == 0 || m.ReleaseYear == year {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func TestImporterMatch(t *testing.T) {
+	im := NewImporter(nil, nil, fakeMatcher{
+		byIMDb: map[string]*domain.MovieMetadata{
+			"tt1375666": {TMDBID: 27205, MediaType: "movie"},
+			"tt0903747": {TMDBID: 1396, MediaType: "tv"},
+		},
+		search: map[string][]domain.MovieMetadata{
+			"Perfect Days": {{TMDBID: 976893, Title: "Perfect Days", ReleaseYear: 2023}},
+			"Intersteller": {{TMDBID: 157336, Title: "Interstellar", ReleaseYear: 2014}},
+			"Dune": {
+				{TMDBID: 841, Title: "Dune", ReleaseYear: 1984},
+				{TMDBID: 438631, Title: "Dune", ReleaseYear: 2021},
+			},
+			"Crash": {
+				{TMDBID: 1, Title: "Crash", ReleaseYear: 1996},
+				{TMDBID: 2, Title: "Crash", ReleaseYear: 2004},
+			},
+			"Heat": {{TMDBID: 949, Title: "Collateral", ReleaseYear: 2004}},
+		},
+	})
+	tests := []struct {
+		name       string
+		key        matchKey
+		wantID     uint
+		wantBy     string
+		candidates int
+	}{
+		{"tmdb ID", matchKey{tmdbID: 603, title: "The Matrix"}, 603, "tmdb_id", 0},
+		{"imdb ID", matchKey{imdbID: "tt1375666", title: "Inception"}, 27205, "imdb_id", 0},
+		{"imdb ID of a show", matchKey{imdbID: "tt0903747", title: "Breaking Bad"}, 0, "", 0},
+		{"exact", matchKey{title: "Perfect Days", year: 2023}, 976893, "exact", 0},
+		{"year off by one", matchKey{title: "Perfect Days", year: 2022}, 976893, "fuzzy", 0},
+		{"year picks the remake", matchKey{title: "Dune", year: 2021}, 438631, "exact", 0},
+		{"fuzzy", matchKey{title: "Intersteller"}, 157336, "fuzzy", 0},
+		{"ambiguous", matchKey{title: "Crash"}, 0, "", 2},
+		{"no close title", matchKey{title: "Heat"}, 0, "", 0},
+		{"no results", matchKey{title: "Nothing"}, 0, "", 0},
+	}
+	for _, tt := range tests {
+		out := im.match(tt.key)
+		if out.tmdbID != tt.wantID || out.matchedBy != tt.wantBy || len(out.candidates) != tt.candidates {
+			t.Errorf("%s: match = %+v, want ID %d by %q with %d candidates", tt.name, out, tt.wantID, tt.wantBy, tt.candidates)
+		}
+		if tt.wantID == 0 && tt.candidates == 0 && out.reason == "" {
+			t.Errorf("%s: no reason given for the failed match", tt.name)
+		}
+	}
+}
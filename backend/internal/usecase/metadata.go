@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// metadataTTL is how long a cached TMDB entry is served before it is refreshed
+const metadataTTL = 7 * 24 * time.Hour
+
+// Titles the provider couldn't resolve aren't asked for again for a while:
+// ones it doesn't have for a day, ones whose fetch failed for a few minutes
+const (
+	metadataMissTTL  = 24 * time.Hour
+	metadataErrorTTL = 5 * time.Minute
+)
+
+// maxConcurrentFetches bounds parallel TMDB calls when filling the cache
+const maxConcurrentFetches = 4
+
+// MetadataCache serves title metadata from the local table and fills
+// missing or stale entries from the provider on demand.
+type MetadataCache struct {
+	repo     repository.MetadataRepo
+	provider domain.MetadataProvider
+
+	mu     sync.Mutex
+	misses map[uint]time.Time // unresolved IDs and when to try them again
+}
+
+// NewMetadataCache creates a cache. provider may be nil, in which case only
+// already cached entries are returned.
+func NewMetadataCache(repo repository.MetadataRepo, provider domain.MetadataProvider) *MetadataCache {
+	return &MetadataCache{repo: repo, provider: provider, misses: map[uint]time.Time{}}
+}
+
+// StaleBefore returns the fetch time before which cached entries are refreshed
+func (mc *MetadataCache) StaleBefore() time.Time {
+	return time.Now().Add(-metadataTTL)
+}
+
+// Cached returns the stored metadata keyed by TMDB ID, however old, without
+// asking the provider
+func (mc *MetadataCache) Cached(ids []uint) (map[uint]domain.MovieMetadata, error) {
+	cached, err := mc.repo.GetMetadataByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]domain.MovieMetadata, len(cached))
+	for _, m := range cached {
+		result[m.TMDBID] = m
+	}
+	return result, nil
+}
+
+// Lookup returns metadata keyed by TMDB ID. IDs that can't be resolved are
+// omitted and not retried until their miss expires; a stale entry is kept if
+// refreshing it fails.
+func (mc *MetadataCache) Lookup(ids []uint) (map[uint]domain.MovieMetadata, error) {
+	cached, err := mc.repo.GetMetadataByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]domain.MovieMetadata, len(ids))
+	for _, m := range cached {
+		result[m.TMDBID] = m
+	}
+	if mc.provider == nil {
+		return result, nil
+	}
+
+	var missing []uint
+	now := time.Now()
+	mc.mu.Lock()
+	for id, retryAt := range mc.misses {
+		if now.After(retryAt) {
+			delete(mc.misses, id)
+		}
+	}
+	for _, id := range ids {
+		if _, missed := mc.misses[id]; missed {
+			continue
+		}
+		m, ok := result[id]
+		if !ok || now.Sub(m.FetchedAt) > metadataTTL {
+			missing = append(missing, id)
+		}
+	}
+	mc.mu.Unlock()
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, id := range missing {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id uint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			m, err := mc.provider.FetchMetadata(id)
+			if err != nil || m == nil {
+				ttl := metadataMissTTL
+				if err != nil {
+					log.Printf("metadata fetch %d: %v", id, err)
+					ttl = metadataErrorTTL
+				}
+				mc.mu.Lock()
+				mc.misses[id] = time.Now().Add(ttl)
+				mc.mu.Unlock()
+				return
+			}
+			if err := mc.repo.UpsertMetadata(m); err != nil {
+				log.Printf("metadata store %d: %v", id, err)
+			}
+			mu.Lock()
+			result[id] = *m
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+	return result, nil
+}
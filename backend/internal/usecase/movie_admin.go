@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+// ErrInvalidMovie wraps the reason an admin's movie was rejected
+var ErrInvalidMovie = errors.New("invalid movie")
+
+// Limits on admin-entered movie fields
+const (
+	maxMovieTitle       = 300
+	maxMovieDescription = 10000
+	maxMovieCast        = 50
+	maxCastName         = 200
+	minMovieYear        = 1870
+	maxMovieYear        = 2100
+)
+
+// maxMovieHistory bounds how many audit entries MovieHistory returns
+const maxMovieHistory = 200
+
+func invalidMovie(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidMovie, fmt.Sprintf(format, args...))
+}
+
+// normalizeIMDbID lowercases and checks an IMDb ID; "" stays empty
+func normalizeIMDbID(id string) (string, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if id != "" && !imdbIDPattern.MatchString(id) {
+		return "", invalidMovie("invalid IMDb ID")
+	}
+	return id, nil
+}
+
+func validateMediaType(mediaType string) error {
+	if mediaType != "movie" && mediaType != "tv" {
+		return invalidMovie("media type must be movie or tv")
+	}
+	return nil
+}
+
+func validateTitle(field, title string, required bool) error {
+	if required && title == "" {
+		return invalidMovie("%s is required", field)
+	}
+	if utf8.RuneCountInString(title) > maxMovieTitle {
+		return invalidMovie("%s must be at most %d characters", field, maxMovieTitle)
+	}
+	return nil
+}
+
+func validateDescription(description string) error {
+	if utf8.RuneCountInString(description) > maxMovieDescription {
+		return invalidMovie("description must be at most %d characters", maxMovieDescription)
+	}
+	return nil
+}
+
+// validateYear accepts 0 for an unknown year
+func validateYear(year int) error {
+	if year != 0 && (year < minMovieYear || year > maxMovieYear) {
+		return invalidMovie("year must be between %d and %d", minMovieYear, maxMovieYear)
+	}
+	return nil
+}
+
+// cleanCastNames trims names and drops empty ones
+func cleanCastNames(names []string) ([]string, error) {
+	if len(names) > maxMovieCast {
+		return nil, invalidMovie("at most %d cast names", maxMovieCast)
+	}
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if utf8.RuneCountInString(name) > maxCastName {
+			return nil, invalidMovie("cast names must be at most %d characters", maxCastName)
+		}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+// checkGenres rejects genre IDs that aren't in the Genre table
+func (s *MovieUsecase) checkGenres(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	genres, err := s.genresRepo.ListGenres("")
+	if err != nil {
+		return err
+	}
+	known := make(map[uint]bool, len(genres))
+	for _, g := range genres {
+		known[g.ID] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return invalidMovie("unknown genre %d", id)
+		}
+	}
+	return nil
+}
+
+// GetMovie returns a stored movie, including deleted ones; nil if there's none
+func (s *MovieUsecase) GetMovie(id uint) (*domain.Movie, error) {
+	return s.adminRepo.GetMovieIncludingDeleted(id)
+}
+
+// ListMovies pages through stored movies, newest first, with the total count
+func (s *MovieUsecase) ListMovies(q domain.MovieListQuery) ([]domain.Movie, int64, error) {
+	q.Title = strings.TrimSpace(q.Title)
+	return s.moviesRepo.ListMovies(q)
+}
+
+// UpdateMovie validates and applies an admin's edits, recording them under userID
+func (s *MovieUsecase) UpdateMovie(userID, id uint, patch domain.MoviePatch) (*domain.Movie, error) {
+	if patch.MediaType != nil {
+		if err := validateMediaType(*patch.MediaType); err != nil {
+			return nil, err
+		}
+	}
+	if patch.IMDbID != nil {
+		imdbID, err := normalizeIMDbID(*patch.IMDbID)
+		if err != nil {
+			return nil, err
+		}
+		patch.IMDbID = &imdbID
+	}
+	if patch.Title != nil {
+		title := strings.TrimSpace(*patch.Title)
+		if err := validateTitle("title", title, true); err != nil {
+			return nil, err
+		}
+		patch.Title = &title
+	}
+	if patch.OriginalTitle != nil {
+		title := strings.TrimSpace(*patch.OriginalTitle)
+		if err := validateTitle("original title", title, false); err != nil {
+			return nil, err
+		}
+		patch.OriginalTitle = &title
+	}
+	if patch.Description != nil {
+		if err := validateDescription(*patch.Description); err != nil {
+			return nil, err
+		}
+	}
+	if patch.Year != nil {
+		if err := validateYear(*patch.Year); err != nil {
+			return nil, err
+		}
+	}
+	if patch.CastNames != nil {
+		names, err := cleanCastNames(*patch.CastNames)
+		if err != nil {
+			return nil, err
+		}
+		patch.CastNames = &names
+	}
+	if patch.GenreIDs != nil {
+		if err := s.checkGenres(*patch.GenreIDs); err != nil {
+			return nil, err
+		}
+	}
+	return s.adminRepo.UpdateMovie(id, patch, userID)
+}
+
+// DeleteMovie hides a movie from listings and search, recording who did it
+func (s *MovieUsecase) DeleteMovie(userID, id uint) error {
+	return s.adminRepo.DeleteMovie(id, userID)
+}
+
+// RestoreMovie brings back a deleted movie, recording who did it
+func (s *MovieUsecase) RestoreMovie(userID, id uint) (*domain.Movie, error) {
+	return s.adminRepo.RestoreMovie(id, userID)
+}
+
+// MovieHistory returns the admin changes to a movie, newest first
+func (s *MovieUsecase) MovieHistory(id uint) ([]domain.MovieAudit, error) {
+	return s.adminRepo.ListMovieAudits(id, maxMovieHistory)
+}
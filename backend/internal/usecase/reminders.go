@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// releaseDateTTL is how long stored release dates are trusted; dates move
+// often before release, so they are refreshed daily
+const releaseDateTTL = 24 * time.Hour
+
+// fallbackRegion is used when TMDB has no date for the user's own region
+const fallbackRegion = "US"
+
+// ReleaseReminders notifies users about watchlisted movies that are about to
+// come out in their region: once when the release is leadDays or fewer away
+// and again on the day. Notifications are stored in-app and, for users who
+// opted in, also emailed through sender.
+type ReleaseReminders struct {
+	repo     repository.NotificationRepo
+	metadata *MetadataCache
+	dates    domain.ReleaseDateProvider
+	sender   domain.NotificationSender
+	leadDays int
+}
+
+// NewReleaseReminders creates the job. A leadDays of 0 sends only the
+// release-day reminder.
+func NewReleaseReminders(repo repository.NotificationRepo, metadata *MetadataCache, dates domain.ReleaseDateProvider, sender domain.NotificationSender, leadDays int) *ReleaseReminders {
+	return &ReleaseReminders{repo: repo, metadata: metadata, dates: dates, sender: sender, leadDays: leadDays}
+}
+
+// Start runs the job now and then every interval until ctx is cancelled
+func (rr *ReleaseReminders) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if n, err := rr.RunOnce(time.Now()); err != nil {
+			log.Printf("release reminders: %v", err)
+		} else if n > 0 {
+			log.Printf("release reminders: created %d notifications", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce creates the reminders due on now's date, emails those not sent yet
+// and returns how many were new. Running it again the same day creates
+// nothing and only retries failed emails.
+func (rr *ReleaseReminders) RunOnce(now time.Time) (int, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// Regional releases can trail the original by months, so last year's titles still count
+	minYear := today.Year() - 1
+
+	candidates, err := rr.repo.ListReminderCandidates(minYear)
+	if err != nil {
+		return 0, err
+	}
+	seen := map[uint]bool{}
+	var ids []uint
+	for _, c := range candidates {
+		if !seen[c.MovieID] {
+			seen[c.MovieID] = true
+			ids = append(ids, c.MovieID)
+		}
+	}
+	meta, err := rr.metadata.Lookup(ids)
+	if err != nil {
+		return 0, err
+	}
+	var movies []uint
+	for _, id := range ids {
+		m, ok := meta[id]
+		if ok && m.MediaType == "movie" && (m.ReleaseYear == 0 || m.ReleaseYear >= minYear) {
+			movies = append(movies, id)
+		}
+	}
+	dates, err := rr.releaseDates(movies)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, c := range candidates {
+		regions, ok := dates[c.MovieID]
+		if !ok {
+			continue
+		}
+		date, ok := regions[c.Region]
+		if !ok {
+			if date, ok = regions[fallbackRegion]; !ok {
+				continue
+			}
+		}
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		days := int(date.Sub(today).Hours() / 24)
+
+		title := meta[c.MovieID].Title
+		n := domain.Notification{UserID: c.UserID, MovieID: c.MovieID, ReleaseDate: date, Title: title}
+		switch {
+		case days == 0:
+			n.Kind = domain.NotificationReleaseDay
+			n.Message = fmt.Sprintf("%s is out today", title)
+		case days > 0 && days <= rr.leadDays:
+			n.Kind = domain.NotificationReleaseSoon
+			n.Message = fmt.Sprintf("%s comes out %s", title, inDays(days))
+		default:
+			continue
+		}
+
+		isNew, err := rr.repo.CreateNotification(&n)
+		if err != nil {
+			return created, err
+		}
+		if isNew {
+			created++
+		}
+	}
+	return created, rr.sendEmails(today)
+}
+
+// sendEmails emails the notifications created since today that haven't been
+// sent yet, so one that failed is tried again on the next run. Older ones are
+// left alone, as their "comes out in N days" would be out of date.
+func (rr *ReleaseReminders) sendEmails(today time.Time) error {
+	if rr.sender == nil {
+		return nil
+	}
+	pending, err := rr.repo.ListPendingEmails(today)
+	if err != nil {
+		return err
+	}
+	for _, p := range pending {
+		if err := rr.sender.Send(p.To, p.Notification); err != nil {
+			log.Printf("release reminder email to user %d: %v", p.To.UserID, err)
+			continue
+		}
+		if err := rr.repo.MarkNotificationEmailed(p.Notification.ID); err != nil {
+			log.Printf("release reminder %d: %v", p.Notification.ID, err)
+		}
+	}
+	return nil
+}
+
+// releaseDates returns stored dates keyed by TMDB ID and region, fetching
+// titles that have none yet or haven't been checked for a day
+func (rr *ReleaseReminders) releaseDates(ids []uint) (map[uint]map[string]time.Time, error) {
+	stored, err := rr.repo.GetReleaseDates(ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]map[string]time.Time, len(ids))
+	fresh := map[uint]bool{}
+	for _, d := range stored {
+		if result[d.TMDBID] == nil {
+			result[d.TMDBID] = map[string]time.Time{}
+		}
+		result[d.TMDBID][d.Region] = d.Date
+		if time.Since(d.FetchedAt) <= releaseDateTTL {
+			fresh[d.TMDBID] = true
+		}
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, id := range ids {
+		if fresh[id] {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id uint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			dates, err := rr.dates.FetchReleaseDates(id)
+			if err != nil {
+				log.Printf("release dates fetch %d: %v", id, err)
+				return
+			}
+			if err := rr.repo.ReplaceReleaseDates(id, dates); err != nil {
+				log.Printf("release dates store %d: %v", id, err)
+			}
+			regions := make(map[string]time.Time, len(dates))
+			for _, d := range dates {
+				regions[d.Region] = d.Date
+			}
+			mu.Lock()
+			result[id] = regions
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+	return result, nil
+}
+
+func inDays(days int) string {
+	if days == 1 {
+		return "tomorrow"
+	}
+	return fmt.Sprintf("in %d days", days)
+}
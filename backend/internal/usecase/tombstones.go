@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// TombstonePurger permanently deletes watchlist items once they have been
+// removed for longer than retention, i.e. well past the undo window
+type TombstonePurger struct {
+	repo      repository.WatchlistRepo
+	retention time.Duration
+}
+
+func NewTombstonePurger(repo repository.WatchlistRepo, retention time.Duration) *TombstonePurger {
+	return &TombstonePurger{repo: repo, retention: retention}
+}
+
+// Start purges now and then every interval until ctx is cancelled
+func (tp *TombstonePurger) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if n, err := tp.repo.PurgeWatchlistTombstones(time.Now().Add(-tp.retention)); err != nil {
+			log.Printf("watchlist purge: %v", err)
+		} else if n > 0 {
+			log.Printf("watchlist purge: deleted %d removed items", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"log"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// tvCacheTTL is how long cached show and season data are served; airing
+// shows gain episodes and air dates, so they are refreshed daily
+const tvCacheTTL = 24 * time.Hour
+
+// maxContinueWatchingScan bounds how many recently watched shows are checked
+// for a next episode when building the continue-watching row
+const maxContinueWatchingScan = 100
+
+// episodeKey identifies an episode within a show
+type episodeKey struct {
+	season, episode int
+}
+
+func (k episodeKey) after(o episodeKey) bool {
+	return k.season > o.season || (k.season == o.season && k.episode > o.episode)
+}
+
+// TVProgress tracks which episodes users have watched, using TMDB season data
+// cached in the database
+type TVProgress struct {
+	repo     repository.TVRepo
+	provider domain.TVProvider
+}
+
+// NewTVProgress creates the service. provider may be nil, in which case only
+// already cached shows can be tracked.
+func NewTVProgress(repo repository.TVRepo, provider domain.TVProvider) *TVProgress {
+	return &TVProgress{repo: repo, provider: provider}
+}
+
+// Progress returns the user's progress through every season of a show, or
+// repository.ErrNotFound if the show is unknown
+func (tp *TVProgress) Progress(userID, showID uint, now time.Time) (*domain.ShowProgress, error) {
+	show, seasons, err := tp.show(showID)
+	if err != nil {
+		return nil, err
+	}
+	if show == nil {
+		return nil, repository.ErrNotFound
+	}
+	rows, err := tp.repo.ListEpisodeProgress(userID, showID)
+	if err != nil {
+		return nil, err
+	}
+	watched := watchedEpisodes(rows)
+
+	episodes := make(map[int][]domain.TVEpisode, len(seasons))
+	p := newShowProgress(show, seasons, rows)
+	for _, se := range seasons {
+		eps, err := tp.episodes(se)
+		if err != nil {
+			return nil, err
+		}
+		episodes[se.SeasonNumber] = eps
+		sp := domain.SeasonProgress{TVSeason: se, Episodes: make([]domain.EpisodeStatus, len(eps))}
+		for i, ep := range eps {
+			sp.Episodes[i] = domain.EpisodeStatus{TVEpisode: ep}
+			if at, ok := watched[episodeKey{ep.SeasonNumber, ep.EpisodeNumber}]; ok {
+				sp.Episodes[i].Watched = true
+				sp.Episodes[i].WatchedAt = &at
+				sp.Watched++
+			}
+		}
+		p.Seasons = append(p.Seasons, sp)
+	}
+
+	p.NextUp, err = nextUp(seasons, watched, func(se domain.TVSeason) ([]domain.TVEpisode, error) {
+		return episodes[se.SeasonNumber], nil
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ContinueWatching returns shows the user has started and has an aired
+// episode to watch next, most recently watched first. Seasons are left out.
+func (tp *TVProgress) ContinueWatching(userID uint, limit int, now time.Time) ([]domain.ShowProgress, error) {
+	activity, err := tp.repo.ListShowActivity(userID, maxContinueWatchingScan)
+	if err != nil {
+		return nil, err
+	}
+	result := []domain.ShowProgress{}
+	for _, a := range activity {
+		if len(result) >= limit {
+			break
+		}
+		show, seasons, err := tp.show(a.ShowID)
+		if err != nil {
+			log.Printf("continue watching: show %d: %v", a.ShowID, err)
+			continue
+		}
+		if show == nil {
+			continue
+		}
+		rows, err := tp.repo.ListEpisodeProgress(userID, a.ShowID)
+		if err != nil {
+			return nil, err
+		}
+		next, err := nextUp(seasons, watchedEpisodes(rows), tp.episodes, now)
+		if err != nil {
+			log.Printf("continue watching: show %d: %v", a.ShowID, err)
+			continue
+		}
+		if next == nil {
+			continue
+		}
+		p := newShowProgress(show, seasons, rows)
+		p.NextUp = next
+		result = append(result, *p)
+	}
+	return result, nil
+}
+
+// MarkEpisode marks one episode watched or unwatched. Unknown shows, seasons
+// and episodes are reported as repository.ErrNotFound.
+func (tp *TVProgress) MarkEpisode(userID, showID uint, season, episode int, watched bool) error {
+	eps, err := tp.seasonEpisodes(showID, season)
+	if err != nil {
+		return err
+	}
+	for _, ep := range eps {
+		if ep.EpisodeNumber == episode {
+			_, err := tp.repo.SetEpisodesWatched(userID, showID, season, []int{episode}, watched)
+			return err
+		}
+	}
+	return repository.ErrNotFound
+}
+
+// MarkSeason marks every aired episode of a season watched, or every episode
+// unwatched, and returns how many changed
+func (tp *TVProgress) MarkSeason(userID, showID uint, season int, watched bool, now time.Time) (int64, error) {
+	eps, err := tp.seasonEpisodes(showID, season)
+	if err != nil {
+		return 0, err
+	}
+	numbers := make([]int, 0, len(eps))
+	for _, ep := range eps {
+		if !watched || ep.Aired(now) {
+			numbers = append(numbers, ep.EpisodeNumber)
+		}
+	}
+	return tp.repo.SetEpisodesWatched(userID, showID, season, numbers, watched)
+}
+
+// seasonEpisodes returns one season's episodes, or repository.ErrNotFound
+func (tp *TVProgress) seasonEpisodes(showID uint, season int) ([]domain.TVEpisode, error) {
+	show, seasons, err := tp.show(showID)
+	if err != nil {
+		return nil, err
+	}
+	if show == nil {
+		return nil, repository.ErrNotFound
+	}
+	for _, se := range seasons {
+		if se.SeasonNumber == season {
+			return tp.episodes(se)
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// show returns a show and its seasons from the cache, refreshing them from
+// the provider when missing or stale. A nil show means it doesn't exist.
+func (tp *TVProgress) show(showID uint) (*domain.TVShow, []domain.TVSeason, error) {
+	cached, err := tp.repo.GetTVShow(showID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cached != nil && (tp.provider == nil || time.Since(cached.FetchedAt) <= tvCacheTTL) {
+		seasons, err := tp.repo.GetTVSeasons(showID)
+		return cached, seasons, err
+	}
+	if tp.provider == nil {
+		return nil, nil, nil
+	}
+
+	show, seasons, err := tp.provider.FetchShow(showID)
+	if err != nil {
+		if cached == nil {
+			return nil, nil, err
+		}
+		log.Printf("tv show fetch %d: %v", showID, err)
+		seasons, err := tp.repo.GetTVSeasons(showID)
+		return cached, seasons, err
+	}
+	if show == nil {
+		return nil, nil, nil
+	}
+	if err := tp.repo.ReplaceTVShow(show, seasons); err != nil {
+		return nil, nil, err
+	}
+	// Re-read so seasons carry their cached episodes' fetch times
+	seasons, err = tp.repo.GetTVSeasons(showID)
+	return show, seasons, err
+}
+
+// episodes returns a season's episodes from the cache, refreshing them from
+// the provider when missing or stale. A failed refresh serves the stale copy.
+func (tp *TVProgress) episodes(season domain.TVSeason) ([]domain.TVEpisode, error) {
+	fresh := season.EpisodesFetchedAt != nil && time.Since(*season.EpisodesFetchedAt) <= tvCacheTTL
+	if fresh || tp.provider == nil {
+		return tp.repo.GetTVEpisodes(season.ShowID, season.SeasonNumber)
+	}
+	eps, err := tp.provider.FetchSeason(season.ShowID, season.SeasonNumber)
+	if err != nil {
+		log.Printf("tv season fetch %d/%d: %v", season.ShowID, season.SeasonNumber, err)
+		return tp.repo.GetTVEpisodes(season.ShowID, season.SeasonNumber)
+	}
+	if err := tp.repo.ReplaceTVEpisodes(season.ShowID, season.SeasonNumber, eps); err != nil {
+		return nil, err
+	}
+	return eps, nil
+}
+
+// nextUp returns the first aired, unwatched episode after the furthest one
+// watched, skipping specials. Seasons are loaded only as far as needed.
+func nextUp(seasons []domain.TVSeason, watched map[episodeKey]time.Time, load func(domain.TVSeason) ([]domain.TVEpisode, error), now time.Time) (*domain.TVEpisode, error) {
+	var furthest episodeKey
+	for k := range watched {
+		if k.season > 0 && k.after(furthest) {
+			furthest = k
+		}
+	}
+	for _, se := range seasons {
+		if se.SeasonNumber == 0 || se.SeasonNumber < furthest.season {
+			continue
+		}
+		eps, err := load(se)
+		if err != nil {
+			return nil, err
+		}
+		for _, ep := range eps {
+			k := episodeKey{ep.SeasonNumber, ep.EpisodeNumber}
+			if !k.after(furthest) {
+				continue
+			}
+			if _, ok := watched[k]; ok {
+				continue
+			}
+			if !ep.Aired(now) {
+				return nil, nil
+			}
+			return &ep, nil
+		}
+	}
+	return nil, nil
+}
+
+// newShowProgress fills in the counts shared by both progress views
+func newShowProgress(show *domain.TVShow, seasons []domain.TVSeason, rows []domain.EpisodeProgress) *domain.ShowProgress {
+	p := &domain.ShowProgress{Show: *show}
+	for _, se := range seasons {
+		if se.SeasonNumber > 0 {
+			p.Total += se.EpisodeCount
+		}
+	}
+	for _, row := range rows {
+		if row.SeasonNumber > 0 {
+			p.Watched++
+		}
+		if p.LastWatchedAt == nil || row.WatchedAt.After(*p.LastWatchedAt) {
+			at := row.WatchedAt
+			p.LastWatchedAt = &at
+		}
+	}
+	return p
+}
+
+func watchedEpisodes(rows []domain.EpisodeProgress) map[episodeKey]time.Time {
+	watched := make(map[episodeKey]time.Time, len(rows))
+	for _, row := range rows {
+		watched[episodeKey{row.SeasonNumber, row.EpisodeNumber}] = row.WatchedAt
+	}
+	return watched
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+)
+
+func TestNextUp(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	aired := now.AddDate(0, -1, 0)
+	upcoming := now.AddDate(0, 0, 7)
+	episodes := map[int][]domain.TVEpisode{
+		0: {{SeasonNumber: 0, EpisodeNumber: 1, AirDate: &aired}},
+		1: {
+			{SeasonNumber: 1, EpisodeNumber: 1, AirDate: &aired},
+			{SeasonNumber: 1, EpisodeNumber: 2, AirDate: &aired},
+			{SeasonNumber: 1, EpisodeNumber: 3, AirDate: &aired},
+		},
+		2: {
+			{SeasonNumber: 2, EpisodeNumber: 1, AirDate: &aired},
+			{SeasonNumber: 2, EpisodeNumber: 2, AirDate: &upcoming},
+			{SeasonNumber: 2, EpisodeNumber: 3},
+		},
+	}
+	seasons := []domain.TVSeason{{SeasonNumber: 0}, {SeasonNumber: 1}, {SeasonNumber: 2}}
+
+	tests := []struct {
+		name    string
+		watched []episodeKey
+		want    *episodeKey
+		loads   int // seasons whose episodes were needed
+	}{
+		{"nothing watched starts at the pilot", nil, &episodeKey{1, 1}, 1},
+		{"specials don't count", []episodeKey{{0, 1}}, &episodeKey{1, 1}, 1},
+		{"next in the season", []episodeKey{{1, 1}}, &episodeKey{1, 2}, 1},
+		{"gaps before the furthest are skipped", []episodeKey{{1, 2}}, &episodeKey{1, 3}, 1},
+		{"rolls over to the next season", []episodeKey{{1, 1}, {1, 3}}, &episodeKey{2, 1}, 2},
+		{"unaired episode means nothing is up", []episodeKey{{2, 1}}, nil, 1},
+		{"caught up", []episodeKey{{2, 3}}, nil, 1},
+	}
+	for _, tt := range tests {
+		watched := map[episodeKey]time.Time{}
+		for _, k := range tt.watched {
+			watched[k] = aired
+		}
+		loads := 0
+		load := func(se domain.TVSeason) ([]domain.TVEpisode, error) {
+			loads++
+			return episodes[se.SeasonNumber], nil
+		}
+		got, err := nextUp(seasons, watched, load, now)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		switch {
+		case tt.want == nil && got != nil:
+			t.Errorf("%s: next up = S%dE%d, want none", tt.name, got.SeasonNumber, got.EpisodeNumber)
+		case tt.want != nil && got == nil:
+			t.Errorf("%s: next up = none, want S%dE%d", tt.name, tt.want.season, tt.want.episode)
+		case tt.want != nil && (got.SeasonNumber != tt.want.season || got.EpisodeNumber != tt.want.episode):
+			t.Errorf("%s: next up = S%dE%d, want S%dE%d", tt.name, got.SeasonNumber, got.EpisodeNumber, tt.want.season, tt.want.episode)
+		}
+		if loads != tt.loads {
+			t.Errorf("%s: loaded %d seasons, want %d", tt.name, loads, tt.loads)
+		}
+	}
+}
+
+func TestNextUpLoadError(t *testing.T) {
+	boom := errors.New("TMDB is down")
+	_, err := nextUp([]domain.TVSeason{{SeasonNumber: 1}}, nil, func(domain.TVSeason) ([]domain.TVEpisode, error) {
+		return nil, boom
+	}, time.Now())
+	if !errors.Is(err, boom) {
+		t.Errorf("err = %v, want %v", err, boom)
+	}
+}
@@ -1,7 +1,12 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/HMZ-H/moviemate/internal/domain"
 	"github.com/HMZ-H/moviemate/internal/repository"
@@ -10,11 +15,13 @@ import (
 type MovieUsecase struct {
 	usersRepo     repository.UserRepo
 	moviesRepo    repository.MovieRepo
+	adminRepo     repository.MovieAdminRepo
+	genresRepo    repository.GenreRepo
 	watchlistRepo repository.WatchlistRepo
 }
 
-func NewMovieUsecase(u repository.UserRepo, m repository.MovieRepo, w repository.WatchlistRepo) *MovieUsecase {
-	return &MovieUsecase{usersRepo: u, moviesRepo: m, watchlistRepo: w}
+func NewMovieUsecase(u repository.UserRepo, m repository.MovieRepo, a repository.MovieAdminRepo, g repository.GenreRepo, w repository.WatchlistRepo) *MovieUsecase {
+	return &MovieUsecase{usersRepo: u, moviesRepo: m, adminRepo: a, genresRepo: g, watchlistRepo: w}
 }
 
 // Create User
@@ -36,29 +43,140 @@ func (uc *MovieUsecase) CreateUser(username, email string) (*domain.User, error)
 	return u, nil
 }
 
-// AddMovie with unique title check
-func (s *MovieUsecase) AddMovie(m *domain.Movie) (*domain.Movie, error) {
-	exist, err := s.moviesRepo.GetMovieByTitle(m.Title)
+// imdbIDPattern matches IMDb title IDs such as tt0133093
+var imdbIDPattern = regexp.MustCompile(`^tt[0-9]{7,10}$`)
+
+// AddMovie creates a movie, or updates the stored one with the same TMDB or
+// IMDb ID, restoring it if it was deleted. Titles may repeat (remakes), so a
+// movie without external IDs is always created. The change is recorded
+// under userID. It reports whether the movie is new.
+func (s *MovieUsecase) AddMovie(userID uint, m *domain.Movie) (*domain.Movie, bool, error) {
+	m.Title = strings.TrimSpace(m.Title)
+	if err := validateTitle("title", m.Title, true); err != nil {
+		return nil, false, err
+	}
+	m.OriginalTitle = strings.TrimSpace(m.OriginalTitle)
+	if err := validateTitle("original title", m.OriginalTitle, false); err != nil {
+		return nil, false, err
+	}
+	if m.MediaType == "" {
+		m.MediaType = "movie"
+	}
+	if err := validateMediaType(m.MediaType); err != nil {
+		return nil, false, err
+	}
+	if m.TMDBID != nil && *m.TMDBID == 0 {
+		m.TMDBID = nil
+	}
+	if m.IMDbID != nil {
+		id, err := normalizeIMDbID(*m.IMDbID)
+		if err != nil {
+			return nil, false, err
+		}
+		m.IMDbID = nil
+		if id != "" {
+			m.IMDbID = &id
+		}
+	}
+	if err := validateDescription(m.Description); err != nil {
+		return nil, false, err
+	}
+	if err := validateYear(m.Year); err != nil {
+		return nil, false, err
+	}
+	if m.CastNames != nil {
+		names, err := cleanCastNames(m.CastNames)
+		if err != nil {
+			return nil, false, err
+		}
+		m.CastNames = names
+	}
+	ids := make([]uint, len(m.Genres))
+	for i, g := range m.Genres {
+		ids[i] = g.ID
+	}
+	if err := s.checkGenres(ids); err != nil {
+		return nil, false, err
+	}
+	m.ID = 0
+	created, err := s.adminRepo.AdminUpsertMovie(m, userID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	if exist != nil {
-		return nil, errors.New("movie title already exists")
+	return m, created, nil
+}
+
+// FindMovies searches stored movies by title and, when non-zero, year
+func (s *MovieUsecase) FindMovies(title string, year int) ([]domain.Movie, error) {
+	return s.moviesRepo.FindMoviesByTitle(strings.TrimSpace(title), year)
+}
+
+// ListMoviesByGenre pages through movies having all of genreIDs, most popular first
+func (s *MovieUsecase) ListMoviesByGenre(genreIDs []uint, limit, offset int) ([]domain.Movie, error) {
+	return s.moviesRepo.ListMoviesByGenre(genreIDs, limit, offset)
+}
+
+// SearchMovies runs a full-text search over stored movies. When nothing
+// matches, likely because of a typo, it falls back to fuzzy title matching
+// and reports fuzzy as true.
+func (s *MovieUsecase) SearchMovies(q domain.MovieSearchQuery) (hits []domain.MovieSearchHit, total int64, fuzzy bool, err error) {
+	q.Query = strings.TrimSpace(q.Query)
+	hits, total, err = s.moviesRepo.SearchMovies(q)
+	if err != nil || total > 0 {
+		return hits, total, false, err
 	}
-	if err := s.moviesRepo.CreateMovie(m); err != nil {
-		return nil, err
+	hits, total, err = s.moviesRepo.FuzzySearchMovies(q)
+	return hits, total, true, err
+}
+
+// Suggest completes a search box prefix with titles and people. Both
+// lookups run at once; one still running when budget is up is cancelled
+// and left empty, and the result is marked partial.
+func (s *MovieUsecase) Suggest(ctx context.Context, prefix string, limit int, budget time.Duration) (*domain.Suggestions, error) {
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+	prefix = strings.TrimSpace(prefix)
+
+	out := &domain.Suggestions{Titles: []domain.TitleSuggestion{}, People: []domain.PersonSuggestion{}}
+	var titlesErr, peopleErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		var titles []domain.TitleSuggestion
+		if titles, titlesErr = s.moviesRepo.SuggestTitles(ctx, prefix, limit); titlesErr == nil {
+			out.Titles = titles
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var people []domain.PersonSuggestion
+		if people, peopleErr = s.moviesRepo.SuggestPeople(ctx, prefix, limit); peopleErr == nil {
+			out.People = people
+		}
+	}()
+	wg.Wait()
+
+	for _, err := range []error{titlesErr, peopleErr} {
+		if err == nil {
+			continue
+		}
+		if ctx.Err() == nil {
+			return nil, err // a failure rather than the budget running out
+		}
+		out.Partial = true
 	}
-	return m, nil
+	return out, nil
 }
 
 // Watchlist operations
-func (s *MovieUsecase) AddToWatchlist(userID, movieID uint) error {
-	item := &domain.WatchlistItem{UserID: userID, MovieID: movieID}
+func (s *MovieUsecase) AddToWatchlist(userID, movieID uint) (bool, error) {
+	item := &domain.WatchlistItem{UserID: userID, MovieID: movieID, AddedByID: userID}
 	return s.watchlistRepo.AddWatchlist(item)
 }
-func (s *MovieUsecase) RemoveFromWatchlist(userID, movieID uint) error {
-	return s.watchlistRepo.RemoveWatchlist(userID, movieID)
+func (s *MovieUsecase) RemoveFromWatchlist(userID, movieID uint) (bool, error) {
+	return s.watchlistRepo.RemoveWatchlist(domain.ListRef{OwnerID: userID}, movieID)
 }
-func (s *MovieUsecase) GetWatchlist(userID uint) ([]domain.Movie, error) {
-	return s.watchlistRepo.ListWatchlistByUser(userID)
+func (s *MovieUsecase) GetWatchlist(userID uint) ([]domain.WatchlistItem, error) {
+	return s.watchlistRepo.ListWatchlistByUser(userID, domain.WatchlistFilter{})
 }
@@ -13,6 +13,20 @@ import (
 func main() {
 	// Load .env if present (ignore error if missing)
 	_ = godotenv.Load()
+	// "sync-catalog" runs one catalog sync pass instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "sync-catalog" {
+		if err := runCatalogSync(os.Args[2:]); err != nil {
+			log.Fatalf("catalog sync: %v", err)
+		}
+		return
+	}
+	// "set-role <username> <role>" promotes or demotes a user
+	if len(os.Args) > 1 && os.Args[1] == "set-role" {
+		if err := runSetRole(os.Args[2:]); err != nil {
+			log.Fatalf("set role: %v", err)
+		}
+		return
+	}
 	// Initialize DB if DATABASE_URL is set (optional for chatbot)
 	if os.Getenv("DATABASE_URL") != "" {
 		if _, err := infra.NewDB(); err != nil {
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/HMZ-H/moviemate/internal/domain"
+	"github.com/HMZ-H/moviemate/internal/infra"
+	"github.com/HMZ-H/moviemate/internal/repository"
+)
+
+// runSetRole gives a user a role, e.g. "set-role alice admin". There's no
+// API for it, so the first admin has to be made here.
+func runSetRole(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: set-role <username> <user|admin>")
+	}
+	username, role := args[0], args[1]
+	if role != domain.UserRoleUser && role != domain.UserRoleAdmin {
+		return fmt.Errorf("role must be %s or %s", domain.UserRoleUser, domain.UserRoleAdmin)
+	}
+
+	db, err := infra.NewDB()
+	if err != nil {
+		return err
+	}
+	repo := repository.NewGormRepo(db)
+	user, err := repo.GetByUsername(username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("no user named %q", username)
+	}
+	if err := repo.SetUserRole(user.ID, role); err != nil {
+		return err
+	}
+	log.Printf("%s is now %s", username, role)
+	return nil
+}
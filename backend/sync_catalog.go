@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	delivery "github.com/HMZ-H/moviemate/internal/delivery"
+	"github.com/HMZ-H/moviemate/internal/infra"
+	"github.com/HMZ-H/moviemate/internal/repository"
+	"github.com/HMZ-H/moviemate/internal/usecase"
+)
+
+// runCatalogSync syncs the Movie table from a TMDB export once. Flags
+// override the CATALOG_SYNC_* settings; an interrupt stops the run after
+// saving progress, and the next run resumes from there.
+func runCatalogSync(args []string) error {
+	exportPath, opts := delivery.CatalogSyncOptionsFromEnv()
+	fs := flag.NewFlagSet("sync-catalog", flag.ExitOnError)
+	fs.StringVar(&exportPath, "export", exportPath, "TMDB movie ID export file, or a directory of them")
+	fs.IntVar(&opts.MaxFetches, "max-fetches", opts.MaxFetches, "detail fetches this run (0 for no limit)")
+	fs.Float64Var(&opts.MinPopularity, "min-popularity", opts.MinPopularity, "skip export entries less popular than this")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if exportPath == "" {
+		return errors.New("no export: pass -export or set CATALOG_SYNC_EXPORT")
+	}
+
+	db, err := infra.NewDB()
+	if err != nil {
+		return err
+	}
+	client, err := infra.NewTMDBClientFromEnv()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sync := usecase.NewCatalogSync(repository.NewGormRepo(db), infra.NewTMDBCatalogService(client), exportPath, opts)
+	run, err := sync.Run(ctx)
+	if run != nil {
+		log.Printf("%s: %s, %d lines read, %d IDs staged, %d movies fetched, %d failed",
+			run.ExportFile, run.Status, run.LinesRead, run.Ingested, run.Fetched, run.Failed)
+	}
+	return err
+}
@@ -0,0 +1,13 @@
+// TMDB data is fetched through the backend's catalog proxy, which holds the API key
+export const catalogUrl = (path: string) =>
+  `${import.meta.env.VITE_API_URL}/api/catalog/${path}`;
+
+export async function fetchCatalog<T>(path: string): Promise<T> {
+  const res = await fetch(catalogUrl(path), {
+    headers: { accept: "application/json" },
+  });
+  if (!res.ok) {
+    throw new Error(`Catalog request failed: ${res.status}`);
+  }
+  return res.json();
+}
@@ -0,0 +1,14 @@
+// Posters and backdrops are served through the backend's image proxy, which
+// scales TMDB's originals to the preset widths below and caches them
+export type ImageSize =
+  | "w92"
+  | "w154"
+  | "w185"
+  | "w342"
+  | "w500"
+  | "w780"
+  | "w1280"
+  | "original";
+
+export const imageUrl = (size: ImageSize, path: string) =>
+  `${import.meta.env.VITE_API_URL}/api/images/${size}${path}`;
@@ -3,6 +3,8 @@ import { motion } from "framer-motion";
 import { useNavigate } from "react-router-dom";
 import type { TMDBItem, TMDBVideo } from "../types/tmdb";
 import TrailerModal from "./TrailerModal";
+import { fetchCatalog } from "../api/catalog";
+import { imageUrl } from "../api/images";
 
 interface FeaturedMovieGridProps {
   title: string;
@@ -25,24 +27,8 @@ const FeaturedMovieGrid: React.FC<FeaturedMovieGridProps> = ({
     
     setLoadingTrailer(movie.id);
     try {
-      const apiKey = import.meta.env.VITE_TMDB_API_KEY || import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
       const type = movie.media_type || 'movie';
-      
-      const videoUrl = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-        ? `https://api.themoviedb.org/3/${type}/${movie.id}/videos`
-        : `https://api.themoviedb.org/3/${type}/${movie.id}/videos?api_key=${apiKey}`;
-
-      const headers: Record<string, string> = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-        ? {
-            Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`,
-            accept: "application/json",
-          }
-        : {
-            accept: "application/json",
-          };
-
-      const res = await fetch(videoUrl, { headers });
-      const data = await res.json();
+      const data = await fetchCatalog<{ results?: TMDBVideo[] }>(`${type}/${movie.id}/videos`);
       
       if (data.results) {
         const trailers = data.results.filter(
@@ -91,7 +77,7 @@ const FeaturedMovieGrid: React.FC<FeaturedMovieGridProps> = ({
               movie.first_air_date?.split("-")[0] ||
               "N/A";
             const posterUrl = movie.poster_path
-              ? `https://image.tmdb.org/t/p/w500${movie.poster_path}`
+              ? imageUrl("w500", movie.poster_path)
               : "/placeholder.jpg";
             const rating = movie.vote_average || 0;
 
@@ -4,6 +4,8 @@ import { Navigation } from "swiper/modules";
 import { useNavigate } from "react-router-dom";
 import type { TMDBItem, TMDBVideo } from "../types/tmdb"; 
 import TrailerModal from "./TrailerModal";
+import { fetchCatalog } from "../api/catalog";
+import { imageUrl } from "../api/images";
 
 import "swiper/swiper-bundle.css";
 
@@ -23,24 +25,8 @@ const MovieSection: React.FC<MovieSectionProps> = ({ title, movies }) => {
     
     setLoadingTrailer(movie.id);
     try {
-      const apiKey = import.meta.env.VITE_TMDB_API_KEY || import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
       const type = movie.media_type || 'movie';
-      
-      const videoUrl = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-        ? `https://api.themoviedb.org/3/${type}/${movie.id}/videos`
-        : `https://api.themoviedb.org/3/${type}/${movie.id}/videos?api_key=${apiKey}`;
-
-      const headers: Record<string, string> = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-        ? {
-            Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`,
-            accept: "application/json",
-          }
-        : {
-            accept: "application/json",
-          };
-
-      const res = await fetch(videoUrl, { headers });
-      const data = await res.json();
+      const data = await fetchCatalog<{ results?: TMDBVideo[] }>(`${type}/${movie.id}/videos`);
       
       if (data.results) {
         const trailers = data.results.filter(
@@ -106,7 +92,7 @@ const MovieSection: React.FC<MovieSectionProps> = ({ title, movies }) => {
             movie.first_air_date?.split("-")[0] ||
             "N/A";
           const posterUrl = movie.poster_path
-            ? `https://image.tmdb.org/t/p/w500${movie.poster_path}`
+            ? imageUrl("w500", movie.poster_path)
             : "/placeholder.jpg";
           const rating = movie.vote_average || 0;
 
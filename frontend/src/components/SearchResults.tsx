@@ -1,5 +1,6 @@
 import React from "react";
 import { Link } from "react-router-dom";
+import { imageUrl } from "../api/images";
 
 // 🎬 TMDb Movie/TV interface
 export interface TMDBItem {
@@ -39,7 +40,7 @@ const SearchResults: React.FC<SearchResultsProps> = ({ results }) => {
               <img
                 src={
                   item.poster_path
-                    ? `https://image.tmdb.org/t/p/w500${item.poster_path}`
+                    ? imageUrl("w500", item.poster_path)
                     : "/placeholder.jpg"
                 }
                 alt={title || "Untitled"}
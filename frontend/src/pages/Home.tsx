@@ -7,6 +7,8 @@ import Typewriter from "typewriter-effect";
 import MovieSection from "../components/MovieSection";
 import FeaturedMovieGrid from "../components/FeaturedMovieGrid";
 import Logo from "../components/Logo";
+import { fetchCatalog } from "../api/catalog";
+import { imageUrl } from "../api/images";
 // import type { TMDBItem } from "../types/tmdb"
 
 interface Movie {
@@ -36,41 +38,11 @@ const Home: React.FC = () => {
   const [imageLoading, setImageLoading] = useState(true);
   const [imageError, setImageError] = useState(false);
 
-  const fetchFromTMDB = async (endpoint: string, setter: React.Dispatch<React.SetStateAction<Movie[]>>) => {
+  const fetchFromCatalog = async (endpoint: string, setter: React.Dispatch<React.SetStateAction<Movie[]>>) => {
     try {
-      const apiKey = import.meta.env.VITE_TMDB_API_KEY || import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
-      
-      console.log("API Key present:", !!apiKey);
-      console.log("Fetching endpoint:", endpoint);
-      
-      if (!apiKey) {
-        console.error("No TMDB API key found. Please set VITE_TMDB_API_KEY or VITE_TMDB_READ_ACCESS_TOKEN in your .env file");
-        return;
-      }
-
-      const url = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-        ? `https://api.themoviedb.org/3/${endpoint}`
-        : `https://api.themoviedb.org/3/${endpoint}${endpoint.includes('?') ? '&' : '?'}api_key=${apiKey}`;
-
-      const headers: Record<string, string> = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-        ? {
-          Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`,
-          accept: "application/json",
-          }
-        : {
-            accept: "application/json",
-          };
-
-      console.log("Fetching URL:", url);
-      const res = await fetch(url, { headers });
-      console.log("Response status:", res.status);
-      
-      const data = await res.json();
-      console.log("Response data:", data);
-      
+      const data = await fetchCatalog<{ results?: Movie[] }>(endpoint);
       if (data.results) {
         setter(data.results);
-        console.log(`Successfully loaded ${data.results.length} items for ${endpoint}`);
       } else {
         console.error("No results in response:", data);
         setter([]);
@@ -84,11 +56,11 @@ const Home: React.FC = () => {
     // Add a small delay to show loading state
     const timer = setTimeout(async () => {
       await Promise.all([
-        fetchFromTMDB("trending/all/week?language=en-US", setTrending),
-        fetchFromTMDB("movie/top_rated?language=en-US&page=1", setTopRated),
-        fetchFromTMDB("discover/movie?primary_release_date.gte=2025-04-01&primary_release_date.lte=2025-09-01", setNewReleases),
-        fetchFromTMDB("movie/upcoming?language=en-US&page=1", setComingSoon),
-        fetchFromTMDB("tv/popular?language=en-US&page=1", setRecommended)
+        fetchFromCatalog("trending?media_type=all&window=week", setTrending),
+        fetchFromCatalog("movie/top_rated", setTopRated),
+        fetchFromCatalog("discover?media_type=movie&released_from=2025-04-01&released_to=2025-09-01", setNewReleases),
+        fetchFromCatalog("movie/upcoming", setComingSoon),
+        fetchFromCatalog("tv/popular", setRecommended)
       ]);
       setIsLoading(false);
     }, 100);
@@ -98,34 +70,12 @@ const Home: React.FC = () => {
 
 const fetchHeroImage = async () => {
   try {
-    const apiKey =
-      import.meta.env.VITE_TMDB_API_KEY ||
-      import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
-
-    if (!apiKey) {
-      setImageError(true);
-      return;
-    }
-
-    const movieUrl = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN
-      ? `https://api.themoviedb.org/3/movie/popular?language=en-US&page=1`
-      : `https://api.themoviedb.org/3/movie/popular?api_key=${apiKey}&language=en-US&page=1`;
-
-    const headers: Record<string, string> = import.meta.env
-      .VITE_TMDB_READ_ACCESS_TOKEN
-      ? {
-          Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`,
-          accept: "application/json",
-        }
-      : { accept: "application/json" };
-
-    const movieRes = await fetch(movieUrl, { headers });
-    const movieData = await movieRes.json();
+    const movieData = await fetchCatalog<{ results?: Movie[] }>("movie/popular");
 
     if (movieData.results && movieData.results.length > 0) {
       const featuredMovie = movieData.results[0];
-      const imageUrl = `https://image.tmdb.org/t/p/original${featuredMovie.backdrop_path}`;
-      setHeroImage(imageUrl);
+      const heroUrl = imageUrl("w1280", featuredMovie.backdrop_path);
+      setHeroImage(heroUrl);
     } else {
       setImageError(true);
     }
@@ -359,7 +309,7 @@ useEffect(() => {
               {trending.slice(0, 10).map((movie, index) => {
                 const displayTitle = movie.title || movie.name;
                 const posterUrl = movie.poster_path
-                  ? `https://image.tmdb.org/t/p/w500${movie.poster_path}`
+                  ? imageUrl("w500", movie.poster_path)
                   : "/placeholder.jpg";
                 const rating = movie.vote_average || 0;
 
@@ -410,7 +360,7 @@ useEffect(() => {
                   onClick={() => navigate(`/${trending[0]?.media_type || "movie"}/${trending[0]?.id}`)}
                 >
                   <img
-                    src={trending[0]?.poster_path ? `https://image.tmdb.org/t/p/original${trending[0]?.poster_path}` : "/placeholder.jpg"}
+                    src={trending[0]?.poster_path ? imageUrl("w780", trending[0]?.poster_path) : "/placeholder.jpg"}
                     alt={trending[0]?.title || "Featured Movie"}
                     className="w-full h-96 object-cover transition-transform duration-500 group-hover:scale-110"
                   />
@@ -440,7 +390,7 @@ useEffect(() => {
                 {trending.slice(1, 4).map((movie) => {
                   const displayTitle = movie.title || movie.name;
                   const posterUrl = movie.poster_path
-                    ? `https://image.tmdb.org/t/p/w500${movie.poster_path}`
+                    ? imageUrl("w500", movie.poster_path)
                     : "/placeholder.jpg";
 
                   return (
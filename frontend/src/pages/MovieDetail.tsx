@@ -3,6 +3,8 @@ import { useParams, useNavigate } from "react-router-dom";
 import type { TMDBItem, TMDBVideo } from "../types/tmdb";
 import TrailerModal from "../components/TrailerModal";
 import { useAuth } from "../contexts/AuthContext";
+import { fetchCatalog } from "../api/catalog";
+import { imageUrl } from "../api/images";
 
 function MovieDetails() {
   const { id, media_type } = useParams<{ id: string; media_type: "movie" | "tv" }>();
@@ -23,16 +25,6 @@ function MovieDetails() {
   useEffect(() => {
     async function fetchMovie() {
       try {
-        const apiKey = import.meta.env.VITE_TMDB_API_KEY || import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
-        
-        console.log("Movie Detail Debug:", { id, media_type, apiKey: !!apiKey });
-        
-        if (!apiKey) {
-          console.error("No TMDB API key found. Please set VITE_TMDB_API_KEY or VITE_TMDB_READ_ACCESS_TOKEN in your .env file");
-          setLoading(false);
-          return;
-        }
-
         if (!id) {
           console.error("No movie ID provided");
           setLoading(false);
@@ -41,83 +33,49 @@ function MovieDetails() {
 
         // Default to movie if media_type is not provided
         const type = media_type || 'movie';
-        
-        const url = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-          ? `https://api.themoviedb.org/3/${type}/${id}?append_to_response=credits,videos`
-          : `https://api.themoviedb.org/3/${type}/${id}?api_key=${apiKey}&append_to_response=credits,videos`;
 
-        const headers: Record<string, string> = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-          ? {
-              Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`,
-              accept: "application/json",
-            }
-          : {
-              accept: "application/json",
-            };
+        let data: TMDBItem;
+        try {
+          data = await fetchCatalog<TMDBItem>(`${type}/${id}`);
+        } catch (error) {
+          // Try to fetch as movie if it failed as TV show
+          if (type !== 'tv') throw error;
+          console.log("Retrying as movie...");
+          data = await fetchCatalog<TMDBItem>(`movie/${id}`);
+        }
 
-        console.log("Fetching movie details for:", { id, type, url });
-        const res = await fetch(url, { headers });
-        console.log("Movie detail response status:", res.status);
-        
-        if (!res.ok) {
-          throw new Error(`HTTP error! status: ${res.status}`);
+        setMovie(data);
+
+        // Set up background images
+        const bgImages = [];
+        if (data.backdrop_path) {
+          bgImages.push(imageUrl("w1280", data.backdrop_path));
         }
-        
-        const data = await res.json();
-        console.log("Movie detail data:", data);
-        
-        if (data.id) {
-          setMovie(data);
-          
-          // Set up background images
-          const bgImages = [];
-          if (data.backdrop_path) {
-            bgImages.push(`https://image.tmdb.org/t/p/original${data.backdrop_path}`);
-          }
-          if (data.poster_path) {
-            bgImages.push(`https://image.tmdb.org/t/p/original${data.poster_path}`);
-          }
-          setBackgroundImages(bgImages);
-          setBackgroundLoading(false);
+        if (data.poster_path) {
+          bgImages.push(imageUrl("w780", data.poster_path));
+        }
+        setBackgroundImages(bgImages);
+        setBackgroundLoading(false);
+
+        // Find the best trailer from videos
+        if (data.videos && data.videos.results) {
+          const trailers = data.videos.results.filter(
+            (video: TMDBVideo) => 
+              video.type === 'Trailer' && 
+              video.site === 'YouTube' && 
+              video.official
+          );
           
-          // Find the best trailer from videos
-          if (data.videos && data.videos.results) {
-            const trailers = data.videos.results.filter(
-              (video: TMDBVideo) => 
-                video.type === 'Trailer' && 
-                video.site === 'YouTube' && 
-                video.official
+          if (trailers.length > 0) {
+            // Prefer official trailers, then any trailer
+            setTrailer(trailers[0]);
+          } else {
+            // Fallback to any YouTube video
+            const youtubeVideos = data.videos.results.filter(
+              (video: TMDBVideo) => video.site === 'YouTube'
             );
-            
-            if (trailers.length > 0) {
-              // Prefer official trailers, then any trailer
-              setTrailer(trailers[0]);
-            } else {
-              // Fallback to any YouTube video
-              const youtubeVideos = data.videos.results.filter(
-                (video: TMDBVideo) => video.site === 'YouTube'
-              );
-              if (youtubeVideos.length > 0) {
-                setTrailer(youtubeVideos[0]);
-              }
-            }
-          }
-        } else {
-          console.error("Invalid movie data received:", data);
-          // Try to fetch as movie if it failed as TV show
-          if (type === 'tv') {
-            console.log("Retrying as movie...");
-            const movieUrl = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-              ? `https://api.themoviedb.org/3/movie/${id}?append_to_response=credits`
-              : `https://api.themoviedb.org/3/movie/${id}?api_key=${apiKey}&append_to_response=credits`;
-            
-            const movieRes = await fetch(movieUrl, { headers });
-            if (movieRes.ok) {
-              const movieData = await movieRes.json();
-              if (movieData.id) {
-                setMovie(movieData);
-                return;
-              }
+            if (youtubeVideos.length > 0) {
+              setTrailer(youtubeVideos[0]);
             }
           }
         }
@@ -170,29 +128,13 @@ function MovieDetails() {
     if (!id || !movie) return;
     
     try {
-      const apiKey = import.meta.env.VITE_TMDB_API_KEY || import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
       const type = media_type || 'movie';
-      
-      const imagesUrl = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-        ? `https://api.themoviedb.org/3/${type}/${id}/images`
-        : `https://api.themoviedb.org/3/${type}/${id}/images?api_key=${apiKey}`;
-
-      const headers: Record<string, string> = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-        ? {
-            Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`,
-            accept: "application/json",
-          }
-        : {
-            accept: "application/json",
-          };
-
-      const res = await fetch(imagesUrl, { headers });
-      const data = await res.json();
+      const data = await fetchCatalog<{ backdrops?: { file_path: string }[] }>(`${type}/${id}/images`);
       
       if (data.backdrops && data.backdrops.length > 0) {
         const additionalImages = data.backdrops
           .slice(0, 5) // Get top 5 backdrop images
-          .map((backdrop: { file_path: string }) => `https://image.tmdb.org/t/p/original${backdrop.file_path}`);
+          .map((backdrop: { file_path: string }) => imageUrl("w1280", backdrop.file_path));
         
         setBackgroundImages(prev => [...prev, ...additionalImages]);
       }
@@ -271,24 +213,8 @@ function MovieDetails() {
     
     setLoadingTrailer(true);
     try {
-      const apiKey = import.meta.env.VITE_TMDB_API_KEY || import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
       const type = media_type || 'movie';
-      
-      const videoUrl = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-        ? `https://api.themoviedb.org/3/${type}/${id}/videos`
-        : `https://api.themoviedb.org/3/${type}/${id}/videos?api_key=${apiKey}`;
-
-            const headers: Record<string, string> = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-              ? {
-                  Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`,
-                  accept: "application/json",
-                }
-              : {
-                  accept: "application/json",
-                };
-
-      const res = await fetch(videoUrl, { headers });
-      const data = await res.json();
+      const data = await fetchCatalog<{ results?: TMDBVideo[] }>(`${type}/${id}/videos`);
       
       if (data.results) {
         const trailers = data.results.filter(
@@ -423,7 +349,7 @@ function MovieDetails() {
           <img
             src={
               movie.poster_path
-                ? `https://image.tmdb.org/t/p/w500${movie.poster_path}`
+                ? imageUrl("w500", movie.poster_path)
                 : "/placeholder.jpg"
             }
             alt={title}
@@ -2,6 +2,8 @@ import { useEffect, useState, useCallback } from 'react'
 import { motion } from 'framer-motion'
 import { useNavigate } from 'react-router-dom'
 import type { TMDBItem } from '../types/tmdb'
+import { fetchCatalog } from '../api/catalog'
+import { imageUrl } from '../api/images'
 
 const Recommendations = () => {
     const navigate = useNavigate()
@@ -32,39 +34,17 @@ const Recommendations = () => {
     const fetchRecommendations = useCallback(async () => {
         setLoading(true)
         try {
-            const apiKey = import.meta.env.VITE_TMDB_API_KEY || import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
-            
-            if (!apiKey) {
-                console.error("No TMDB API key found. Please set VITE_TMDB_API_KEY or VITE_TMDB_READ_ACCESS_TOKEN in your .env file");
-                setLoading(false);
-                return;
-            }
-
-            let endpoint = 'discover/movie?sort_by=popularity.desc&include_adult=false&language=en-US&page=1'
+            let endpoint = 'discover?media_type=movie&sort_by=popularity.desc'
             
             if (selectedGenre !== 'all') {
-                endpoint += `&with_genres=${selectedGenre}`
+                endpoint += `&genres=${selectedGenre}`
             }
             
             if (selectedYear !== 'all') {
-                endpoint += `&primary_release_year=${selectedYear}`
+                endpoint += `&year=${selectedYear}`
             }
 
-            const url = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-                ? `https://api.themoviedb.org/3/${endpoint}`
-                : `https://api.themoviedb.org/3/${endpoint}&api_key=${apiKey}`;
-
-            const headers: Record<string, string> = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-                ? {
-                    Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`,
-                    accept: "application/json",
-                }
-                : {
-                    accept: "application/json",
-                };
-
-            const res = await fetch(url, { headers })
-            const data = await res.json()
+            const data = await fetchCatalog<{ results?: TMDBItem[] }>(endpoint)
             setRecommendations(data.results || [])
         } catch (error) {
             console.error("Error fetching recommendations:", error)
@@ -171,7 +151,7 @@ const Recommendations = () => {
                             const title = movie.title || movie.name
                             const year = movie.release_date?.split("-")[0] || movie.first_air_date?.split("-")[0] || "N/A"
                             const posterUrl = movie.poster_path
-                                ? `https://image.tmdb.org/t/p/w500${movie.poster_path}`
+                                ? imageUrl('w500', movie.poster_path)
                                 : "/placeholder.jpg"
                             const rating = movie.vote_average || 0
 
@@ -1,6 +1,7 @@
 import { useEffect, useState } from "react";
 import { useSearchParams, useNavigate } from "react-router-dom";
 import SearchResults from "../components/SearchResults";
+import { fetchCatalog } from "../api/catalog";
 
 interface Movie {
   id: number;
@@ -24,35 +25,9 @@ export default function SearchPage() {
       if (!query) return;
       setLoading(true);
       console.log("Searching for:", query);
-      console.log("API Key:", import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN ? "Present" : "Missing");
       
       try {
-        // Use API key instead of Bearer token if read access token is not available
-        const apiKey = import.meta.env.VITE_TMDB_API_KEY || import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
-        
-        if (!apiKey) {
-          console.error("No TMDB API key found. Please set VITE_TMDB_API_KEY or VITE_TMDB_READ_ACCESS_TOKEN in your .env file");
-          setResults([]);
-          return;
-        }
-
-        const url = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-          ? `https://api.themoviedb.org/3/search/multi?query=${encodeURIComponent(query)}&include_adult=false&language=en-US&page=1`
-          : `https://api.themoviedb.org/3/search/multi?api_key=${apiKey}&query=${encodeURIComponent(query)}&include_adult=false&language=en-US&page=1`;
-
-        const headers: Record<string, string> = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-          ? {
-              Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`,
-              accept: "application/json",
-            }
-          : {
-              accept: "application/json",
-            };
-
-        const res = await fetch(url, { headers });
-        
-        console.log("Response status:", res.status);
-        const data = await res.json();
+        const data = await fetchCatalog<{ results?: Movie[] }>(`search?q=${encodeURIComponent(query)}`);
         console.log("Search results:", data);
         
         if (data.results) {
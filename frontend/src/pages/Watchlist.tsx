@@ -1,8 +1,8 @@
 import React, { useState, useEffect } from 'react';
 import { useAuth } from '../contexts/AuthContext';
 import { useNavigate } from 'react-router-dom';
+import { imageUrl } from '../api/images';
 
-type TMDBId = number;
 interface WatchlistItemUI {
   id: number;
   title: string;
@@ -12,6 +12,16 @@ interface WatchlistItemUI {
   media_type?: string;
 }
 
+interface WatchlistDetailsItem {
+  movie_id: number;
+  media_type?: string;
+  title?: string;
+  poster_path?: string;
+  year?: number;
+  runtime?: number;
+  genres: string[];
+}
+
 const Watchlist: React.FC = () => {
   const [watchlist, setWatchlist] = useState<WatchlistItemUI[]>([]);
   const [loading, setLoading] = useState(true);
@@ -23,7 +33,7 @@ const Watchlist: React.FC = () => {
     if (!token) return;
     
     try {
-      const response = await fetch(`${import.meta.env.VITE_API_URL}/api/watchlist`, {
+      const response = await fetch(`${import.meta.env.VITE_API_URL}/api/watchlist?expand=details&limit=200`, {
         headers: {
           'Authorization': `Bearer ${token}`,
           'Content-Type': 'application/json',
@@ -32,38 +42,15 @@ const Watchlist: React.FC = () => {
       
       if (response.ok) {
         const data = await response.json();
-        const ids: TMDBId[] = data.items || [];
-        // fetch details from TMDB for each id
-        const apiKey = import.meta.env.VITE_TMDB_API_KEY || import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN;
-        const headers: Record<string, string> = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN 
-          ? { Authorization: `Bearer ${import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN}`, accept: 'application/json' }
-          : { accept: 'application/json' };
-        const fetchOne = async (id:number): Promise<WatchlistItemUI|null> => {
-          try {
-            // Try movie first then tv
-            const movieUrl = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN ? 
-              `https://api.themoviedb.org/3/movie/${id}` :
-              `https://api.themoviedb.org/3/movie/${id}?api_key=${apiKey}`;
-            let res = await fetch(movieUrl, { headers });
-            if (res.ok) {
-              const m = await res.json();
-              return { id: m.id, title: m.title, year: m.release_date, poster: m.poster_path ? `https://image.tmdb.org/t/p/w500${m.poster_path}` : undefined, overview: m.overview, media_type: 'movie' };
-            }
-            const tvUrl = import.meta.env.VITE_TMDB_READ_ACCESS_TOKEN ? 
-              `https://api.themoviedb.org/3/tv/${id}` :
-              `https://api.themoviedb.org/3/tv/${id}?api_key=${apiKey}`;
-            res = await fetch(tvUrl, { headers });
-            if (res.ok) {
-              const t = await res.json();
-              return { id: t.id, title: t.name, year: t.first_air_date, poster: t.poster_path ? `https://image.tmdb.org/t/p/w500${t.poster_path}` : undefined, overview: t.overview, media_type: 'tv' };
-            }
-          } catch (e) {
-            console.error('TMDB fetch error', e);
-          }
-          return null;
-        };
-        const detailed = (await Promise.all(ids.map(fetchOne))).filter(Boolean) as WatchlistItemUI[];
-        setWatchlist(detailed);
+        const items: WatchlistDetailsItem[] = data.items || [];
+        setWatchlist(items.map((m) => ({
+          id: m.movie_id,
+          title: m.title || `#${m.movie_id}`,
+          year: m.year ? String(m.year) : undefined,
+          poster: m.poster_path ? imageUrl('w500', m.poster_path) : undefined,
+          overview: [m.runtime ? `${m.runtime} min` : '', m.genres.join(', ')].filter(Boolean).join(' · '),
+          media_type: m.media_type,
+        })));
       } else {
         console.error('Failed to fetch watchlist:', response.status);
       }